@@ -0,0 +1,39 @@
+package api_controllers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"portfolio-backend/app/responses"
+	"portfolio-backend/services/captcha"
+)
+
+type CaptchaController struct {
+	Guard *captcha.GuardedVerifier
+}
+
+func NewCaptchaController(guard *captcha.GuardedVerifier) *CaptchaController {
+	return &CaptchaController{Guard: guard}
+}
+
+// Handler: GET /admin/captcha-scores?action=contact_submit
+func (cc *CaptchaController) ScoreHistogram(w http.ResponseWriter, r *http.Request) {
+	action := r.URL.Query().Get("action")
+	if action == "" {
+		responses.WriteError(w, r, responses.BadRequest("Missing action"))
+		return
+	}
+
+	buckets, err := cc.Guard.ScoreHistogram(action)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "captcha score histogram read failed", "action", action, "error", err)
+		responses.WriteError(w, r, responses.Internal("Failed to read score histogram"))
+		return
+	}
+
+	responses.JSON(w, http.StatusOK, map[string]interface{}{
+		"provider": cc.Guard.Provider(),
+		"action":   action,
+		"buckets":  buckets,
+	})
+}
@@ -1,115 +1,266 @@
 package api_controllers
 
 import (
-    "bytes"
-    "encoding/json"
-    "fmt"
-    "io"
-    "log"
-    "net/http"
-    "os"
-    "strings"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"portfolio-backend/app/requests"
+	"portfolio-backend/app/responses"
+	"portfolio-backend/services/discord"
+	"portfolio-backend/services/metrics"
 )
 
-type DiscordController struct{}
+type DiscordController struct {
+	Client  *discord.Client
+	Queue   *discord.Queue
+	Targets *discord.Targets
+	Limits  discord.UploadLimits
+	Debug   bool // return Discord's error details to clients (DEBUG_DISCORD_PROXY)
+}
 
-func NewDiscordController() *DiscordController {
-	return &DiscordController{}
+func NewDiscordController(client *discord.Client, queue *discord.Queue, targets *discord.Targets, limits discord.UploadLimits, debug bool) *DiscordController {
+	return &DiscordController{Client: client, Queue: queue, Targets: targets, Limits: limits, Debug: debug}
 }
 
+// Handler: POST /api/v1/discord-webhook
 func (dc *DiscordController) SendWebhook(w http.ResponseWriter, r *http.Request) {
-    // Optional API key protection (set DISCORD_PROXY_KEY on server to enable)
-    expectedKey := os.Getenv("DISCORD_PROXY_KEY")
-    if expectedKey != "" {
-        if r.Header.Get("X-API-KEY") != expectedKey {
-            http.Error(w, "Unauthorized", http.StatusUnauthorized)
-            return
-        }
-    }
-
-    // Read raw request body and forward it unchanged so JSON structure is preserved.
-    bodyBytes, err := io.ReadAll(r.Body)
-    if err != nil {
-        http.Error(w, "Invalid request body", http.StatusBadRequest)
-        return
-    }
-    if len(bodyBytes) == 0 {
-        http.Error(w, "Empty request body", http.StatusBadRequest)
-        return
-    }
-
-    // Determine content type once for validation AND for forwarding
-    contentType := r.Header.Get("Content-Type")
-    if contentType == "" {
-        contentType = "application/json"
-    }
-
-    // If JSON, do a light validation to avoid forwarding an empty Discord message
-    if strings.Contains(contentType, "application/json") {
-        var payload map[string]interface{}
-        if err := json.Unmarshal(bodyBytes, &payload); err == nil {
-            // Check for a non-empty `content` string or non-empty `embeds` array
-            hasContent := false
-            if c, ok := payload["content"].(string); ok {
-                if strings.TrimSpace(c) != "" {
-                    hasContent = true
-                }
-            }
-            hasEmbeds := false
-            if e, ok := payload["embeds"]; ok {
-                if arr, ok := e.([]interface{}); ok && len(arr) > 0 {
-                    hasEmbeds = true
-                }
-            }
-            if !hasContent && !hasEmbeds {
-                log.Printf("discord webhook rejected empty message payload: %s", string(bodyBytes))
-                if os.Getenv("DEBUG_DISCORD_PROXY") == "true" {
-                    http.Error(w, fmt.Sprintf("Rejected empty message payload: %s", string(bodyBytes)), http.StatusBadRequest)
-                    return
-                }
-                http.Error(w, "Rejected empty message payload", http.StatusBadRequest)
-                return
-            }
-        }
-    }
-
-    webhookURL := os.Getenv("DISCORD_WEBHOOK_URL")
-    if webhookURL == "" {
-        http.Error(w, "Webhook not configured", http.StatusInternalServerError)
-        return
-    }
-
-    resp, err := http.Post(webhookURL, contentType, bytes.NewBuffer(bodyBytes))
-    if err != nil {
-        // network / DNS / TLS error
-        log.Printf("discord webhook post error: %v", err)
-        http.Error(w, "Failed to send webhook (post error)", http.StatusBadGateway)
-        return
-    }
-    defer resp.Body.Close()
-
-    respBody, _ := io.ReadAll(resp.Body)
-    respText := string(respBody)
-    if len(respText) > 1000 {
-        respText = respText[:1000] + "...(truncated)"
-    }
-
-    if resp.StatusCode >= 300 {
-        // log full details server-side for diagnosis
-        log.Printf("discord webhook failed: status=%d response=%s", resp.StatusCode, respText)
-
-        // Optionally return more detail to the client when debugging is enabled
-        if os.Getenv("DEBUG_DISCORD_PROXY") == "true" {
-            http.Error(w, fmt.Sprintf("Failed to send webhook: status=%d body=%s", resp.StatusCode, respText), http.StatusBadGateway)
-            return
-        }
-
-        http.Error(w, fmt.Sprintf("Failed to send webhook: status=%d", resp.StatusCode), http.StatusBadGateway)
-        return
-    }
-
-    // success
-    log.Printf("discord webhook forwarded successfully: status=%d", resp.StatusCode)
-    w.WriteHeader(http.StatusOK)
-    w.Write([]byte("Webhook sent"))
+	dc.forward(w, r, dc.Targets.Get("default"))
+}
+
+// SendToTarget returns the handler of a named target.
+// Handler: POST /api/v1/discord-webhook/{target}
+func (dc *DiscordController) SendToTarget(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		target := dc.Targets.Get(name)
+		if target == nil {
+			responses.WriteError(w, r, responses.NotFound("Not Found"))
+			return
+		}
+		dc.forward(w, r, target)
+	}
+}
+
+func (dc *DiscordController) forward(w http.ResponseWriter, r *http.Request, target *discord.Target) {
+	// Optional API key protection (set the target's API keys on server to enable)
+	if !target.Authorize(r.Header.Get("X-API-KEY")) {
+		responses.WriteError(w, r, responses.Unauthorized("Unauthorized"))
+		return
+	}
+
+	// Cap the request body; anything larger is answered with 413
+	r.Body = http.MaxBytesReader(w, r.Body, dc.Limits.MaxBodyBytes)
+
+	// Determine content type once for validation AND for forwarding; JSON is assumed when missing
+	if r.Header.Get("Content-Type") == "" {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	if err := requests.RequireContentType(r, "application/json", "multipart/form-data"); err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+	contentType := r.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	webhookURL := target.WebhookURL
+	if webhookURL == "" {
+		responses.WriteError(w, r, responses.Internal("Webhook not configured"))
+		return
+	}
+	wait := r.URL.Query().Get("wait") == "true"
+
+	var bodyBytes []byte
+	var err error
+	switch mediaType {
+	case "multipart/form-data":
+		// File uploads are validated part by part and re-encoded as they are read
+		mr, err := r.MultipartReader()
+		if err != nil {
+			responses.WriteError(w, r, responses.BadRequest("Invalid multipart body"))
+			return
+		}
+		if wait {
+			dc.forwardUpload(w, r, target, webhookURL, mr)
+			return
+		}
+		// Queued uploads are kept for retries, bounded per message and by DISCORD_QUEUE_MAX_BYTES
+		buf := &cappedBuffer{max: dc.Queue.MaxJobBytes}
+		mw := multipart.NewWriter(buf)
+		files, err := discord.CopyMultipart(mw, mr, dc.Limits, target.Render)
+		if err != nil {
+			dc.payloadError(w, r, target, err)
+			return
+		}
+		contentType, bodyBytes = mw.FormDataContentType(), buf.Bytes()
+		slog.InfoContext(r.Context(), "discord webhook upload accepted", "target", target.Name, "files", files)
+
+	default: // application/json
+		contentType = "application/json"
+		if bodyBytes, err = requests.ReadBody(w, r); err != nil {
+			responses.WriteError(w, r, err)
+			return
+		}
+
+		// Targets with a template turn the request JSON into the Discord payload first
+		if bodyBytes, err = target.Render(bodyBytes); err != nil {
+			dc.payloadError(w, r, target, err)
+			return
+		}
+		// Validate against Discord's limits and forward only the sanitized payload
+		msg, err := discord.ParsePayload(bodyBytes)
+		if err != nil {
+			dc.payloadError(w, r, target, err)
+			return
+		}
+		if bodyBytes, err = json.Marshal(msg); err != nil {
+			responses.WriteError(w, r, responses.Internal("Failed to encode payload"))
+			return
+		}
+	}
+
+	// Without ?wait=true the message is queued and retried in the background
+	if !wait {
+		err := dc.Queue.Enqueue(r.Context(), &discord.Job{Target: target.Name, WebhookURL: webhookURL, ContentType: contentType, Body: bodyBytes})
+		if errors.Is(err, discord.ErrJobTooLarge) {
+			dc.payloadError(w, r, target, &http.MaxBytesError{Limit: dc.Queue.MaxJobBytes})
+			return
+		}
+		if err != nil {
+			metrics.DiscordForwards.WithLabelValues(target.Name, "queue", "queue_full").Inc()
+			slog.ErrorContext(r.Context(), "discord webhook enqueue failed", "target", target.Name, "error", err)
+			responses.WriteError(w, r, responses.NewError(http.StatusServiceUnavailable, responses.CodeServiceUnavailable, "Webhook queue is full"))
+			return
+		}
+		metrics.DiscordForwards.WithLabelValues(target.Name, "queue", "queued").Inc()
+		responses.Message(w, http.StatusOK, "Webhook queued")
+		return
+	}
+
+	// The synchronous path never waits: a rate-limited webhook answers 429 with Retry-After
+	result, err := dc.Client.TryExecute(webhookURL, contentType, bodyBytes, true)
+	dc.respond(w, r, target, result, err)
+}
+
+// cappedBuffer buffers a queued upload, failing with a 413 once it would
+// exceed max bytes (0 means no cap).
+type cappedBuffer struct {
+	bytes.Buffer
+	max int64
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if b.max > 0 && int64(b.Len()+len(p)) > b.max {
+		return 0, &http.MaxBytesError{Limit: b.max}
+	}
+	return b.Buffer.Write(p)
+}
+
+// forwardUpload streams a multipart request straight to Discord: parts are
+// validated as they are read and piped into the upstream request, so files
+// are never buffered. An invalid part aborts the upstream request, and the
+// validation error is answered instead of Discord's response.
+func (dc *DiscordController) forwardUpload(w http.ResponseWriter, r *http.Request, target *discord.Target, webhookURL string, mr *multipart.Reader) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	copied := make(chan error, 1)
+	go func() {
+		files, err := discord.CopyMultipart(mw, mr, dc.Limits, target.Render)
+		if err == nil {
+			slog.InfoContext(r.Context(), "discord webhook upload accepted", "target", target.Name, "files", files)
+		}
+		if err != nil {
+			// ValidationErrors is a map, which net/http cannot compare as a body error
+			pw.CloseWithError(errUploadAborted)
+		} else {
+			pw.Close()
+		}
+		copied <- err
+	}()
+
+	result, err := dc.Client.ExecuteStream(webhookURL, mw.FormDataContentType(), pr, true)
+	// Unblock the copy when Discord answered before reading the whole body
+	pr.Close()
+	if copyErr := <-copied; copyErr != nil && !errors.Is(copyErr, io.ErrClosedPipe) {
+		dc.payloadError(w, r, target, copyErr)
+		return
+	}
+	dc.respond(w, r, target, result, err)
+}
+
+// errUploadAborted fails the upstream request of an upload that turned out invalid.
+var errUploadAborted = errors.New("upload aborted")
+
+// respond answers a synchronous (?wait=true) webhook execution.
+func (dc *DiscordController) respond(w http.ResponseWriter, r *http.Request, target *discord.Target, result *discord.ExecuteResult, err error) {
+	if err != nil {
+		var rl *discord.RateLimitError
+		var se *discord.StatusError
+		switch {
+		case errors.As(err, &rl):
+			metrics.DiscordForwards.WithLabelValues(target.Name, "sync", "rate_limited").Inc()
+			slog.WarnContext(r.Context(), "discord webhook rate limited", "target", target.Name, "retry_after", rl.RetryAfter.String())
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(rl.RetryAfter.Seconds()))))
+			responses.WriteError(w, r, responses.TooManyRequests("Discord rate limit reached, retry later"))
+		case errors.As(err, &se):
+			metrics.DiscordForwards.WithLabelValues(target.Name, "sync", "failed").Inc()
+			// log full details server-side for diagnosis
+			slog.ErrorContext(r.Context(), "discord webhook failed", "target", target.Name, "status", se.StatusCode, "response", se.Body)
+
+			// Optionally return more detail to the client when debugging is enabled
+			if dc.Debug {
+				responses.WriteError(w, r, responses.NewError(http.StatusBadGateway, responses.CodeBadGateway, fmt.Sprintf("Failed to send webhook: status=%d body=%s", se.StatusCode, se.Body)))
+				return
+			}
+			responses.WriteError(w, r, responses.NewError(http.StatusBadGateway, responses.CodeBadGateway, fmt.Sprintf("Failed to send webhook: status=%d", se.StatusCode)))
+		default:
+			// network / DNS / TLS error
+			metrics.DiscordForwards.WithLabelValues(target.Name, "sync", "error").Inc()
+			slog.ErrorContext(r.Context(), "discord webhook post error", "target", target.Name, "error", err)
+			responses.WriteError(w, r, responses.NewError(http.StatusBadGateway, responses.CodeBadGateway, "Failed to send webhook (post error)"))
+		}
+		return
+	}
+
+	// success
+	metrics.DiscordForwards.WithLabelValues(target.Name, "sync", "sent").Inc()
+	slog.InfoContext(r.Context(), "discord webhook forwarded successfully", "target", target.Name, "status", result.StatusCode, "message_id", result.MessageID)
+	responses.JSON(w, http.StatusOK, map[string]string{
+		"message": "Webhook sent",
+		"id":      result.MessageID,
+	})
+}
+
+// payloadError answers a request whose body could not be accepted: 413 when
+// it exceeded the size cap, 422 when it broke Discord's limits, 400 otherwise.
+func (dc *DiscordController) payloadError(w http.ResponseWriter, r *http.Request, target *discord.Target, err error) {
+	mode := "queue"
+	if r.URL.Query().Get("wait") == "true" {
+		mode = "sync"
+	}
+	metrics.DiscordForwards.WithLabelValues(target.Name, mode, "rejected").Inc()
+
+	var tooLarge *http.MaxBytesError
+	var vErrs discord.ValidationErrors
+	switch {
+	case errors.As(err, &tooLarge):
+		responses.WriteError(w, r, err)
+	case errors.As(err, &vErrs):
+		slog.WarnContext(r.Context(), "discord webhook rejected invalid payload", "target", target.Name, "errors", vErrs)
+		appErr := responses.NewError(http.StatusUnprocessableEntity, responses.CodeValidationFailed, "The Discord payload is invalid.")
+		appErr.Fields = vErrs
+		responses.WriteError(w, r, appErr)
+	case dc.Debug:
+		responses.WriteError(w, r, responses.BadRequest(fmt.Sprintf("Invalid JSON payload: %v", err)))
+	default:
+		slog.WarnContext(r.Context(), "discord webhook rejected payload", "target", target.Name, "error", err)
+		responses.WriteError(w, r, responses.BadRequest("Invalid JSON payload"))
+	}
 }
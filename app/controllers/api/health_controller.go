@@ -0,0 +1,71 @@
+package api_controllers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"portfolio-backend/app/responses"
+	"portfolio-backend/services/health"
+)
+
+type HealthController struct {
+	Checker   *health.Checker
+	StartedAt time.Time
+}
+
+func NewHealthController(checker *health.Checker) *HealthController {
+	return &HealthController{Checker: checker, StartedAt: time.Now()}
+}
+
+// Handler: GET /healthz
+// Liveness only: answers as long as the process serves requests.
+func (hc *HealthController) Healthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	responses.JSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Handler: GET /readyz
+// Probes Redis, the email templates and SMTP; 503 when any of them fails.
+func (hc *HealthController) Readyz(w http.ResponseWriter, r *http.Request) {
+	healthy, checks := hc.Checker.Check(r.Context())
+
+	status, code := "ok", http.StatusOK
+	if !healthy {
+		status, code = "unavailable", http.StatusServiceUnavailable
+		for _, check := range checks {
+			if !check.Healthy {
+				slog.WarnContext(r.Context(), "readiness check failed", "dependency", check.Name, "error", check.LastError)
+			}
+		}
+	}
+
+	// Failure details stay on /status; the public probe only names the dependencies
+	summary := map[string]string{}
+	for _, check := range checks {
+		summary[check.Name] = "ok"
+		if !check.Healthy {
+			summary[check.Name] = "failing"
+		}
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	responses.JSON(w, code, map[string]interface{}{
+		"status": status,
+		"checks": summary,
+	})
+}
+
+// Handler: GET /status
+// Summarizes each dependency's last success, failure and probe latency.
+func (hc *HealthController) Status(w http.ResponseWriter, r *http.Request) {
+	healthy, checks := hc.Checker.Check(r.Context())
+
+	w.Header().Set("Cache-Control", "no-store")
+	responses.JSON(w, http.StatusOK, map[string]interface{}{
+		"healthy":        healthy,
+		"started_at":     hc.StartedAt.UTC(),
+		"uptime_seconds": int64(time.Since(hc.StartedAt).Seconds()),
+		"dependencies":   checks,
+	})
+}
@@ -1,114 +1,421 @@
 package api_controllers
 
 import (
-	"encoding/json"
-	"log"
-	"net"
+	"context"
+	"errors"
+	"log/slog"
 	"net/http"
-	"strings"
 
+	"portfolio-backend/app/middlewares"
+	"portfolio-backend/app/requests"
+	"portfolio-backend/app/responses"
+	"portfolio-backend/services/captcha"
 	"portfolio-backend/services/email"
+	"portfolio-backend/services/geoip"
+	"portfolio-backend/services/notification"
+	"portfolio-backend/services/spam"
+	"portfolio-backend/services/stepup"
 	validation_email "portfolio-backend/services/validation/email"
-	"portfolio-backend/services/recaptcha"
+	"portfolio-backend/utils"
 )
 
 type EmailController struct {
 	MailService *email.MailService
+	Captcha     captcha.Verifier
+	CaptchaRule captcha.Rule
+	Spam        *spam.Pipeline
+	FormTokens  *spam.FormTokenSigner // nil disables GET /api/v1/send-email/form-token
+	Submissions *spam.SubmissionLog   // recent submissions, labelled spam/ham by admins
+	StepUp      *stepup.Service       // nil disables step-up challenges
+	Notifier    *notification.Dispatcher
+	GeoIP       *geoip.Resolver
+	// StepUpRedirectURL is linked from the page shown after an emailed confirmation; empty shows no link
+	StepUpRedirectURL string
 }
 
-func NewEmailController(mailService *email.MailService) *EmailController {
-	return &EmailController{MailService: mailService}
+func NewEmailController(mailService *email.MailService, verifier captcha.Verifier, rule captcha.Rule, spamPipeline *spam.Pipeline, formTokens *spam.FormTokenSigner, submissions *spam.SubmissionLog, stepUp *stepup.Service, stepUpRedirectURL string, notifier *notification.Dispatcher, geoResolver *geoip.Resolver) *EmailController {
+	return &EmailController{
+		MailService:       mailService,
+		Captcha:           verifier,
+		CaptchaRule:       rule,
+		Spam:              spamPipeline,
+		FormTokens:        formTokens,
+		Submissions:       submissions,
+		StepUp:            stepUp,
+		StepUpRedirectURL: stepUpRedirectURL,
+		Notifier:          notifier,
+		GeoIP:             geoResolver,
+	}
 }
 
 type EmailRequest struct {
-	From    string `json:"from"`
-	Subject string `json:"subject"`
-	Body    string `json:"body"`
-	Name    string `json:"name,omitempty"`
+	From           string `json:"from" validate:"required,email,max=254"`
+	Subject        string `json:"subject" validate:"required,max=255"`
+	Body           string `json:"body" validate:"required,max=10000"`
+	Name           string `json:"name,omitempty" validate:"max=100"`
 	RecaptchaToken string `json:"recaptchaToken,omitempty"`
+	Website        string `json:"website,omitempty"`   // honeypot: hidden field, must stay empty
+	FormToken      string `json:"formToken,omitempty"` // signed render timestamp from GET /api/v1/send-email/form-token
+}
+
+type ChallengeRequest struct {
+	ID             string `json:"id" validate:"required"`
+	RecaptchaToken string `json:"recaptchaToken"`
 }
 
-// Handler: POST /send-email
+// Handler: POST /api/v1/send-email
 func (ec *EmailController) SendEmail(w http.ResponseWriter, r *http.Request) {
 	var req EmailRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
-		return
-	}
-
-	// Determine client IP (prefer X-Forwarded-For / X-Real-IP)
-    clientIP := func(r *http.Request) string {
-        if ip := r.Header.Get("X-Real-IP"); ip != "" {
-            return ip
-        }
-        if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
-            parts := strings.Split(fwd, ",")
-            return strings.TrimSpace(parts[0])
-        }
-        host, _, err := net.SplitHostPort(r.RemoteAddr)
-        if err != nil {
-            return r.RemoteAddr
-        }
-        return host
-    }(r)
+	if err := requests.DecodeJSON(w, r, &req); err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
 
-	clientUserAgent := r.Header.Get("User-Agent")
+	clientIP := utils.ClientIP(r)
 
-	// Verify reCAPTCHA v3 token (action must match client-side action)
-    ok, score, err := recaptcha.Verify(req.RecaptchaToken, "contact_submit", 0.5, clientIP)
-    if err != nil || !ok {
-        log.Printf("recaptcha verification failed: ok=%v score=%v err=%v ip=%s user_agent=%q remote_addr=%s", ok, score, err, clientIP, clientUserAgent, r.RemoteAddr)
-        http.Error(w, "reCAPTCHA verification failed", http.StatusForbidden)
-        return
-    }
+	clientUserAgent := r.Header.Get("User-Agent")
 
-	log.Printf("recaptcha verified: score=%.2f ip=%s user_agent=%q remote_addr=%s", score, clientIP, clientUserAgent, r.RemoteAddr)
+	submission := spam.Submission{
+		Name:      req.Name,
+		Subject:   req.Subject,
+		Body:      req.Body,
+		Honeypot:  req.Website,
+		FormToken: req.FormToken,
+	}
+	verdict := ec.Spam.Evaluate(submission)
 
-	valid, err := validation_email.ValidateEmail(req.From)
+	// Verify captcha token (action and threshold come from the route's captcha rule)
+	result, err := ec.Captcha.Verify(r.Context(), req.RecaptchaToken, clientIP, ec.CaptchaRule)
 	if err != nil {
-		if vErr, ok := err.(*validation_email.ValidationError); ok && vErr.Code == "invalid_format" {
-			http.Error(w, vErr.Message, http.StatusBadRequest)
+		score := 0.0
+		if result != nil {
+			score = result.Score
+		}
+		if verdict.Decision != spam.DecisionReject && ec.StepUp != nil && ec.StepUp.Eligible(result, err) {
+			ec.holdForStepUp(w, r, req, verdict, ec.record(r.Context(), submission, verdict), clientIP, score)
 			return
 		}
-		http.Error(w, "Failed to validate email", http.StatusInternalServerError)
+		slog.WarnContext(r.Context(), "captcha verification failed", "provider", ec.Captcha.Provider(), "score", score, "error", err, "spam", verdict.String(), "user_agent", clientUserAgent, "remote_addr", r.RemoteAddr)
+		responses.WriteError(w, r, errCaptchaFailed)
 		return
 	}
-	if !valid {
-		http.Error(w, "Email is invalid, disposable, or does not exist", http.StatusBadRequest)
+
+	submissionID := ec.record(r.Context(), submission, verdict)
+
+	slog.InfoContext(r.Context(), "captcha verified", "provider", result.Provider, "score", result.Score, "spam", verdict.String(), "submission", submissionID, "user_agent", clientUserAgent, "remote_addr", r.RemoteAddr)
+
+	meta := deliveryMeta{
+		CaptchaScore: result.Score,
+		ClientIP:     clientIP,
+		Country:      ec.GeoIP.FromHeaders(r),
+		Verdict:      &verdict,
+	}
+
+	if verdict.Decision == spam.DecisionReject {
+		ec.notify(r.Context(), notification.EventContactSpamBlocked, contactRequest(req, verdict, submissionID), meta, "")
+		responses.WriteError(w, r, responses.NewError(http.StatusUnprocessableEntity, "spam_rejected", "Message rejected as spam"))
+		return
+	}
+
+	ec.deliver(w, r, contactRequest(req, verdict, submissionID), meta)
+}
+
+// record keeps a submission that passed the captcha (or was held for step-up)
+// so it can be labelled spam/ham to retrain the classifier, returning its ID.
+// Failed captchas are not recorded, so bots cannot fill the log.
+func (ec *EmailController) record(ctx context.Context, submission spam.Submission, verdict spam.Verdict) string {
+	id, err := ec.Submissions.Add(submission, verdict)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record submission", "error", err)
+	}
+	return id
+}
+
+// Handler: GET /api/v1/send-email/form-token
+// Issues the signed render timestamp the contact form submits back as formToken.
+func (ec *EmailController) FormToken(w http.ResponseWriter, r *http.Request) {
+	if ec.FormTokens == nil {
+		responses.WriteError(w, r, responses.NotFound("Not Found"))
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	responses.JSON(w, http.StatusOK, map[string]string{
+		"token": ec.FormTokens.Issue(),
+	})
+}
+
+// Handler: POST /api/v1/send-email/challenge
+// Completes a step-up challenge with an interactive captcha token and delivers the held message.
+func (ec *EmailController) CompleteChallenge(w http.ResponseWriter, r *http.Request) {
+	if ec.StepUp == nil {
+		responses.WriteError(w, r, responses.NotFound("Not Found"))
+		return
+	}
+
+	var req ChallengeRequest
+	if err := requests.DecodeJSON(w, r, &req); err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+
+	pending, err := ec.StepUp.CompleteWithCaptcha(r.Context(), req.ID, req.RecaptchaToken, utils.ClientIP(r))
+	if err != nil {
+		ec.challengeFailed(w, r, req.ID, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "step-up captcha completed", "challenge", pending.ID)
+	if err := ec.deliverHeld(r.Context(), pending); err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+	responses.Message(w, http.StatusCreated, "Your message was sent successfully!")
+}
+
+// Handler: GET /api/v1/send-email/confirm?id=...&token=...
+// Shows the page behind the emailed confirmation link. It only checks the
+// token; the page's button POSTs it back, so link scanners send nothing.
+func (ec *EmailController) ConfirmPage(w http.ResponseWriter, r *http.Request) {
+	if ec.StepUp == nil {
+		responses.WriteError(w, r, responses.NotFound("Not Found"))
+		return
+	}
+
+	id, token := r.URL.Query().Get("id"), r.URL.Query().Get("token")
+	if err := ec.StepUp.CheckEmailToken(id, token); err != nil {
+		ec.challengeFailed(w, r, id, err)
+		return
+	}
+
+	htmlBody, err := ec.MailService.GenerateConfirmationPage(middlewares.CSPNonce(r.Context()), r.URL.Path, id, token)
+	if err != nil {
+		responses.WriteError(w, r, responses.Internal("Failed to render confirmation page"))
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "text/html")
+	w.Write([]byte(htmlBody))
+}
+
+// Handler: POST /api/v1/send-email/confirm (form fields id, token)
+// Completes a step-up challenge from the confirmation page and delivers the held message.
+func (ec *EmailController) ConfirmChallenge(w http.ResponseWriter, r *http.Request) {
+	if ec.StepUp == nil {
+		responses.WriteError(w, r, responses.NotFound("Not Found"))
+		return
+	}
+
+	id := r.PostFormValue("id")
+	pending, err := ec.StepUp.CompleteWithEmail(id, r.PostFormValue("token"))
+	if err != nil {
+		ec.challengeFailed(w, r, id, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "step-up email confirmed", "challenge", pending.ID)
+	if err := ec.deliverHeld(r.Context(), pending); err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+
+	// Answer with a page rather than redirecting: the form's CSP (form-action
+	// 'self') blocks redirects to other origins after a POST
+	htmlBody, err := ec.MailService.GenerateConfirmedPage(middlewares.CSPNonce(r.Context()), ec.StepUpRedirectURL)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to render confirmed page", "error", err)
+		responses.Message(w, http.StatusCreated, "Your message was sent successfully!")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(htmlBody))
+}
+
+// deliverHeld sends a claimed step-up submission. The held entry is removed
+// only once delivery succeeded; on failure it is released for another try.
+func (ec *EmailController) deliverHeld(ctx context.Context, pending *stepup.PendingSubmission) error {
+	if err := ec.send(ctx, pending.Contact, pendingMeta(pending)); err != nil {
+		if rErr := ec.StepUp.Release(pending.ID); rErr != nil {
+			slog.WarnContext(ctx, "failed to release step-up challenge", "challenge", pending.ID, "error", rErr)
+		}
+		return err
+	}
+	if err := ec.StepUp.Finish(pending.ID); err != nil {
+		slog.WarnContext(ctx, "failed to remove delivered step-up challenge", "challenge", pending.ID, "error", err)
+	}
+	return nil
+}
+
+// holdForStepUp parks a borderline submission and answers 428 with the challenge to complete.
+// The sender is validated first, since the email method mails that address.
+func (ec *EmailController) holdForStepUp(w http.ResponseWriter, r *http.Request, req EmailRequest, verdict spam.Verdict, submissionID, clientIP string, score float64) {
+	if err := validateSender(r.Context(), req.From); err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+
+	challenge, err := ec.StepUp.Hold(r.Context(), contactRequest(req, verdict, submissionID), clientIP, score)
+	if errors.Is(err, stepup.ErrRateLimited) {
+		slog.WarnContext(r.Context(), "step-up confirmation emails rate limited", "score", score)
+		responses.WriteError(w, r, responses.TooManyRequests("Too many confirmation emails for this address, please try again later"))
+		return
+	}
+	if errors.Is(err, stepup.ErrMailFailed) {
+		slog.ErrorContext(r.Context(), "step-up confirmation email failed", "score", score, "error", err)
+		responses.WriteError(w, r, responses.NewError(http.StatusServiceUnavailable, responses.CodeServiceUnavailable,
+			"Could not send the confirmation email, please try again later"))
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "step-up hold failed", "score", score, "error", err)
+		responses.WriteError(w, r, errCaptchaFailed)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "step-up challenge issued", "challenge", challenge.ID, "score", score, "spam", verdict.String(), "methods", challenge.Methods)
+	responses.WriteError(w, r, responses.NewError(http.StatusPreconditionRequired, "verification_required",
+		"Additional verification is required before your message can be sent.").WithDetails(map[string]interface{}{
+		"challenge": challenge,
+	}))
+}
+
+func (ec *EmailController) challengeFailed(w http.ResponseWriter, r *http.Request, id string, err error) {
+	slog.WarnContext(r.Context(), "step-up challenge failed", "challenge", id, "error", err)
+	switch {
+	case errors.Is(err, stepup.ErrNotFound):
+		responses.WriteError(w, r, responses.NotFound("Challenge not found or expired"))
+	case errors.Is(err, stepup.ErrInvalidToken):
+		responses.WriteError(w, r, responses.Forbidden("Invalid confirmation link"))
+	case errors.Is(err, stepup.ErrInProgress):
+		responses.WriteError(w, r, responses.NewError(http.StatusConflict, "challenge_in_progress", "This message is already being sent"))
+	default:
+		responses.WriteError(w, r, responses.Forbidden("Verification failed"))
+	}
+}
+
+// deliver validates the sender and sends the contact email, writing the response.
+func (ec *EmailController) deliver(w http.ResponseWriter, r *http.Request, contactReq email.ContactRequest, meta deliveryMeta) {
+	if err := ec.send(r.Context(), contactReq, meta); err != nil {
+		responses.WriteError(w, r, err)
 		return
 	}
 
-	contactReq := email.ContactRequest{
+	responses.Message(w, http.StatusCreated, "Your message was sent successfully!")
+}
+
+// contactRequest builds the mail payload, marking the subject of flagged submissions.
+func contactRequest(req EmailRequest, verdict spam.Verdict, submissionID string) email.ContactRequest {
+	subject := req.Subject
+	if verdict.Decision == spam.DecisionFlag {
+		subject = "[Possible spam] " + subject
+	}
+	return email.ContactRequest{
 		From:    req.From,
-		Subject: req.Subject,
+		Subject: subject,
 		Body:    req.Body,
 		Name:    req.Name,
+
+		SubmissionID: submissionID,
 	}
+}
 
-	err = ec.MailService.SendContactEmail(contactReq)
-	if err != nil {
-		http.Error(w, "Failed to send email", http.StatusInternalServerError)
+// errCaptchaFailed answers submissions whose captcha could not be verified.
+var errCaptchaFailed = responses.NewError(http.StatusForbidden, "captcha_failed", "Captcha verification failed")
+
+// deliveryMeta is what we know about a submission beyond its content, shown in notifications.
+type deliveryMeta struct {
+	CaptchaScore float64
+	ClientIP     string
+	Country      string
+	Verdict      *spam.Verdict
+	// SenderValidated skips validating the sender again, e.g. for held submissions
+	SenderValidated bool
+}
+
+func pendingMeta(pending *stepup.PendingSubmission) deliveryMeta {
+	return deliveryMeta{CaptchaScore: pending.Score, ClientIP: pending.ClientIP, SenderValidated: pending.SenderValidated}
+}
+
+// notify dispatches a contact event to the routed channels in the background;
+// channel failures are logged by the dispatcher and never affect the visitor's response.
+func (ec *EmailController) notify(ctx context.Context, eventType string, contactReq email.ContactRequest, meta deliveryMeta, errMsg string) {
+	if ec.Notifier == nil {
 		return
 	}
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		country := meta.Country
+		if country == "" && ec.GeoIP != nil {
+			country = ec.GeoIP.Lookup(meta.ClientIP)
+		}
+		e := notification.Event{
+			Type: eventType,
+			Contact: notification.Contact{
+				Name:         contactReq.Name,
+				From:         contactReq.From,
+				Subject:      contactReq.Subject,
+				Body:         contactReq.Body,
+				SubmissionID: contactReq.SubmissionID,
+			},
+			CaptchaScore: meta.CaptchaScore,
+			Country:      country,
+			Error:        errMsg,
+		}
+		if meta.Verdict != nil {
+			e.SpamScore = meta.Verdict.Score
+			e.SpamDecision = meta.Verdict.Decision
+		}
+		ec.Notifier.Dispatch(ctx, e)
+	}()
+}
+
+// validateSender rejects malformed, disposable or undeliverable sender addresses.
+func validateSender(ctx context.Context, from string) error {
+	valid, err := validation_email.ValidateEmail(ctx, from)
+	if err != nil {
+		if vErr, ok := err.(*validation_email.ValidationError); ok && vErr.Code == "invalid_format" {
+			return responses.BadRequest(vErr.Message)
+		}
+		return responses.Internal("Failed to validate email")
+	}
+	if !valid {
+		return responses.BadRequest("Email is invalid, disposable, or does not exist")
+	}
+	return nil
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]string{
-		"message": "Your message was sent successfully!",
-	})
+// send validates the sender unless meta says it already was, then mails the
+// submission and notifies the routed channels.
+func (ec *EmailController) send(ctx context.Context, contactReq email.ContactRequest, meta deliveryMeta) error {
+	if !meta.SenderValidated {
+		if err := validateSender(ctx, contactReq.From); err != nil {
+			return err
+		}
+	}
+
+	if err := ec.MailService.SendContactEmail(ctx, contactReq); err != nil {
+		ec.notify(ctx, notification.EventContactDeliveryFailed, contactReq, meta, err.Error())
+		return responses.Internal("Failed to send email")
+	}
+	ec.notify(ctx, notification.EventContactReceived, contactReq, meta, "")
+	return nil
 }
 
 // Handler: GET /preview-email
 func (ec *EmailController) PreviewEmail(w http.ResponseWriter, r *http.Request) {
 	if ec.MailService == nil {
-		log.Println("MailService is nil in PreviewEmail handler")
-		http.Error(w, "Email service not initialized", http.StatusInternalServerError)
+		slog.ErrorContext(r.Context(), "MailService is nil in PreviewEmail handler")
+		responses.WriteError(w, r, responses.Internal("Email service not initialized"))
 		return
 	}
 
-	htmlBody, err := ec.MailService.GeneratePreviewEmail()
+	htmlBody, err := ec.MailService.GeneratePreviewEmail(middlewares.CSPNonce(r.Context()))
 	if err != nil {
-		http.Error(w, "Failed to render email template: "+err.Error(), http.StatusInternalServerError)
+		responses.WriteError(w, r, responses.Internal("Failed to render email template: "+err.Error()))
 		return
 	}
 
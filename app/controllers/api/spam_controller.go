@@ -0,0 +1,124 @@
+package api_controllers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+
+	"portfolio-backend/app/requests"
+	"portfolio-backend/app/responses"
+	"portfolio-backend/services/classifier"
+	"portfolio-backend/services/spam"
+)
+
+type SpamController struct {
+	Classifier  *classifier.Classifier
+	Submissions *spam.SubmissionLog
+
+	// labelMu makes reading a submission's label and retraining on it one
+	// step, so concurrent labels of the same submission can't double count
+	labelMu sync.Mutex
+}
+
+func NewSpamController(bayes *classifier.Classifier, submissions *spam.SubmissionLog) *SpamController {
+	return &SpamController{Classifier: bayes, Submissions: submissions}
+}
+
+// TrainRequest labels either a recorded submission (SubmissionID) or raw text.
+type TrainRequest struct {
+	SubmissionID string `json:"submission_id,omitempty"`
+	Subject      string `json:"subject,omitempty"`
+	Body         string `json:"body,omitempty"`
+	Label        string `json:"label" validate:"required"`
+}
+
+// Handler: POST /admin/spam/train
+func (sc *SpamController) Train(w http.ResponseWriter, r *http.Request) {
+	var req TrainRequest
+	if err := requests.DecodeJSON(w, r, &req); err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+	label := strings.ToLower(req.Label)
+	if label != classifier.LabelSpam && label != classifier.LabelHam {
+		responses.WriteError(w, r, responses.BadRequest(classifier.ErrUnknownLabel.Error()))
+		return
+	}
+
+	if req.SubmissionID != "" {
+		if err := sc.labelSubmission(req.SubmissionID, label); err != nil {
+			if errors.Is(err, spam.ErrSubmissionNotFound) {
+				responses.WriteError(w, r, responses.NotFound(err.Error()))
+				return
+			}
+			slog.ErrorContext(r.Context(), "spam training failed", "submission", req.SubmissionID, "error", err)
+			responses.WriteError(w, r, responses.Internal("Failed to train spam model"))
+			return
+		}
+	} else {
+		if strings.TrimSpace(req.Subject+req.Body) == "" {
+			responses.WriteError(w, r, responses.BadRequest("submission_id or subject/body required"))
+			return
+		}
+		if err := sc.Classifier.Train(req.Subject+"\n"+req.Body, label); err != nil {
+			responses.WriteError(w, r, responses.BadRequest(err.Error()))
+			return
+		}
+		if err := sc.Classifier.Save(); err != nil {
+			slog.ErrorContext(r.Context(), "spam model save failed", "error", err)
+			responses.WriteError(w, r, responses.Internal("Failed to save spam model"))
+			return
+		}
+	}
+
+	slog.InfoContext(r.Context(), "spam model trained", "submission", req.SubmissionID, "label", label)
+	sc.Model(w, r)
+}
+
+// Handler: GET /admin/spam/model
+func (sc *SpamController) Model(w http.ResponseWriter, r *http.Request) {
+	responses.JSON(w, http.StatusOK, sc.Classifier.Stats())
+}
+
+// labelSubmission trains the classifier on a recorded submission, undoing a
+// previous opposite label so corrections don't double count. The model is
+// saved before the label is recorded; if saving fails the training is rolled
+// back, so the label never claims training the model does not have.
+func (sc *SpamController) labelSubmission(id, label string) error {
+	sc.labelMu.Lock()
+	defer sc.labelMu.Unlock()
+
+	rec, err := sc.Submissions.Get(id)
+	if err != nil {
+		return err
+	}
+	if rec.Label == label {
+		return nil
+	}
+	if err := sc.retrain(rec.Text(), rec.Label, label); err != nil {
+		return err
+	}
+	if err := sc.Classifier.Save(); err != nil {
+		if rErr := sc.retrain(rec.Text(), label, rec.Label); rErr != nil {
+			slog.Error("spam model rollback failed", "submission", id, "error", rErr)
+		}
+		return err
+	}
+	rec.Label = label
+	return sc.Submissions.Save(rec)
+}
+
+// retrain moves text from class from (empty when unlabelled) to class to.
+func (sc *SpamController) retrain(text, from, to string) error {
+	if from != "" {
+		if err := sc.Classifier.Untrain(text, from); err != nil {
+			return err
+		}
+	}
+	if to == "" {
+		return nil
+	}
+	return sc.Classifier.Train(text, to)
+}
@@ -0,0 +1,66 @@
+package api_controllers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"portfolio-backend/app/requests"
+	"portfolio-backend/app/responses"
+	"portfolio-backend/services/webhooks"
+)
+
+type WebhookController struct {
+	Service *webhooks.Service
+}
+
+func NewWebhookController(service *webhooks.Service) *WebhookController {
+	return &WebhookController{Service: service}
+}
+
+// RedeliverRequest names the logged delivery to send again.
+type RedeliverRequest struct {
+	DeliveryID string `json:"delivery_id" validate:"required"`
+}
+
+// Handler: GET /admin/webhooks/deliveries?subscription=crm&limit=50
+func (wc *WebhookController) Deliveries(w http.ResponseWriter, r *http.Request) {
+	limit, err := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
+	if err != nil || limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	deliveries, err := wc.Service.Recent(r.URL.Query().Get("subscription"), limit)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "webhook delivery log read failed", "error", err)
+		responses.WriteError(w, r, responses.Internal("Failed to read webhook deliveries"))
+		return
+	}
+
+	responses.JSON(w, http.StatusOK, map[string]interface{}{
+		"deliveries": deliveries,
+	})
+}
+
+// Handler: POST /admin/webhooks/redeliver
+func (wc *WebhookController) Redeliver(w http.ResponseWriter, r *http.Request) {
+	var req RedeliverRequest
+	if err := requests.DecodeJSON(w, r, &req); err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+
+	delivery, err := wc.Service.Redeliver(r.Context(), req.DeliveryID)
+	if errors.Is(err, webhooks.ErrDeliveryNotFound) {
+		responses.WriteError(w, r, responses.NotFound(err.Error()))
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "webhook redelivery failed", "delivery", req.DeliveryID, "error", err)
+		responses.WriteError(w, r, responses.Internal("Failed to redeliver webhook"))
+		return
+	}
+
+	responses.JSON(w, http.StatusOK, delivery)
+}
@@ -0,0 +1,26 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"portfolio-backend/app/responses"
+)
+
+// APIKeyMiddleware only lets requests through whose X-API-KEY header matches key.
+// An empty key disables the wrapped routes entirely (404).
+func APIKeyMiddleware(key string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key == "" {
+				responses.WriteError(w, r, responses.NotFound("Not Found"))
+				return
+			}
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-API-KEY")), []byte(key)) != 1 {
+				responses.WriteError(w, r, responses.Unauthorized("Unauthorized"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Deprecated marks responses of a legacy route with a Deprecation header
+// (RFC 9745), a Sunset header (RFC 8594) when sunset is set, and a Link to
+// the successor route.
+func Deprecated(successor string, deprecatedAt, sunset time.Time) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "@"+strconv.FormatInt(deprecatedAt.Unix(), 10))
+			if !sunset.IsZero() {
+				w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			}
+			w.Header().Add("Link", "<"+successor+`>; rel="successor-version"`)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
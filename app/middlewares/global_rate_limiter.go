@@ -2,29 +2,24 @@ package middlewares
 
 import (
 	"net/http"
+	"portfolio-backend/app/responses"
 	"portfolio-backend/config"
-	"sync"
+	"slices"
 
 	"golang.org/x/time/rate"
 )
 
-var (
-	limiter *rate.Limiter
-	once    sync.Once
-)
-
-func getLimiter() *rate.Limiter {
-	once.Do(func() {
-		cfg := config.GlobalLoadRateLimitingConfig()
-		limiter = rate.NewLimiter(rate.Limit(cfg.RateLimit), cfg.BurstLimit)
-	})
-	return limiter
-}
-
-func GlobalRateLimiter(next http.Handler) http.Handler {
+// GlobalRateLimiter caps the total request rate, except for exemptPaths
+// (health checks) which are always served.
+func GlobalRateLimiter(cfg *config.RateLimitingConfig, next http.Handler, exemptPaths ...string) http.Handler {
+	limiter := rate.NewLimiter(rate.Limit(cfg.RateLimit), cfg.BurstLimit)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !getLimiter().Allow() {
-			http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+		if slices.Contains(exemptPaths, r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !limiter.Allow() {
+			responses.WriteError(w, r, responses.TooManyRequests("Too Many Requests"))
 			return
 		}
 		next.ServeHTTP(w, r)
@@ -0,0 +1,19 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"portfolio-backend/app/requests"
+)
+
+// MaxBodyBytes limits request bodies to n bytes, replacing the decoders'
+// default; reading past it fails and the request decoders answer 413.
+func MaxBodyBytes(n int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = r.WithContext(requests.WithBodyLimit(r.Context(), n))
+			r.Body = http.MaxBytesReader(w, r.Body, n)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"portfolio-backend/services/logging"
+	"portfolio-backend/services/metrics"
+)
+
+// Metrics records the count and latency of every request, labelled with the
+// route pattern stored by RequestID so unknown paths don't explode cardinality.
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		route := "unknown"
+		if info, ok := logging.RequestFromContext(r.Context()); ok && info.Route != "" {
+			route = info.Route
+		}
+		method := methodLabel(r.Method)
+		metrics.HTTPRequests.WithLabelValues(route, method, strconv.Itoa(rec.status)).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(route, method).Observe(time.Since(start).Seconds())
+	})
+}
+
+// methodLabel keeps the method label bounded: clients can send any token as
+// the method, so anything non-standard is counted as OTHER.
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	}
+	return "OTHER"
+}
@@ -1,16 +1,20 @@
 package middlewares
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"net/http"
-	"os"
 	"strconv"
 	"time"
+
+	"portfolio-backend/app/responses"
+	"portfolio-backend/services/metrics"
+	"portfolio-backend/utils"
 )
 
 // RateLimiterService is the interface for rate limiting backends
 type RateLimiterService interface {
-	Allow(key string, limit int, ttl time.Duration) (allowed bool, retryAfter int, err error)
+	Allow(ctx context.Context, key string, limit int, ttl time.Duration) (allowed bool, retryAfter int, err error)
 }
 
 func RateLimitMiddlewareWithKey(
@@ -21,29 +25,23 @@ func RateLimitMiddlewareWithKey(
 ) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if os.Getenv("APP_ENV") == "local" {
-				next.ServeHTTP(w, r)
-				return
-			}
-
-			ip := r.Header.Get("X-Forwarded-For")
-			if ip == "" {
-				ip = r.RemoteAddr
-			}
-			key := "ratelimit:" + baseKey + ":" + ip
+			key := "ratelimit:" + baseKey + ":" + utils.ClientIP(r)
 			limit := rps * burst
 
-			allowed, retryAfter, err := service.Allow(key, limit, ttl)
+			allowed, retryAfter, err := service.Allow(r.Context(), key, limit, ttl)
 			if err != nil {
-				log.Printf("Rate limiter error for key %s: %v", key, err)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				metrics.RateLimitDecisions.WithLabelValues(baseKey, "error").Inc()
+				slog.ErrorContext(r.Context(), "rate limiter error", "key", key, "error", err)
+				responses.WriteError(w, r, responses.Internal("Internal Server Error"))
 				return
 			}
 			if !allowed {
+				metrics.RateLimitDecisions.WithLabelValues(baseKey, "denied").Inc()
 				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
-				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+				responses.WriteError(w, r, responses.TooManyRequests("Too Many Requests"))
 				return
 			}
+			metrics.RateLimitDecisions.WithLabelValues(baseKey, "allowed").Inc()
 
 			next.ServeHTTP(w, r)
 		})
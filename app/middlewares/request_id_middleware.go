@@ -0,0 +1,92 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"portfolio-backend/services/logging"
+	"portfolio-backend/utils"
+)
+
+// validRequestID accepts client supplied IDs that are safe to echo and log.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID tags every request with an X-Request-ID (the client's, if valid,
+// otherwise a generated one), stores it with the client IP and matched route
+// in the request context for logging, and logs the request once it completes.
+func RequestID(mux *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get("X-Request-ID")
+			if !validRequestID.MatchString(id) {
+				id = newRequestID()
+			}
+			w.Header().Set("X-Request-ID", id)
+			trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("request.id", id))
+
+			route := routePattern(mux, r)
+			ctx := logging.WithRequest(r.Context(), logging.RequestInfo{
+				RequestID: id,
+				ClientIP:  utils.ClientIP(r),
+				Route:     route,
+			})
+			r = r.WithContext(ctx)
+
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			level := slog.LevelInfo
+			if rec.status >= 500 {
+				level = slog.LevelError
+			}
+			slog.Log(ctx, level, "request completed",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", rec.status,
+				"duration_ms", time.Since(start).Milliseconds(),
+				"user_agent", r.UserAgent(),
+			)
+		})
+	}
+}
+
+// routePattern returns the path of the mux pattern matching r, without its
+// method ("POST /api/v1/send-email" becomes "/api/v1/send-email"), or "" when
+// no route matches.
+func routePattern(mux *http.ServeMux, r *http.Request) string {
+	_, pattern := mux.Handler(r)
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		return path
+	}
+	return pattern
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// statusRecorder remembers the status code written by the wrapped handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
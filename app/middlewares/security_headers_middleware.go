@@ -0,0 +1,82 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"portfolio-backend/config"
+)
+
+type cspNonceKey struct{}
+
+// CSPNonce returns the nonce allowed by the Content-Security-Policy of an
+// HTML route, or "" when the request has none.
+func CSPNonce(ctx context.Context) string {
+	nonce, _ := ctx.Value(cspNonceKey{}).(string)
+	return nonce
+}
+
+// SecurityHeaders adds HSTS (on HTTPS requests only), X-Content-Type-Options,
+// Referrer-Policy, Permissions-Policy, X-Frame-Options and a
+// Content-Security-Policy to every response. Requests to cfg.HTMLPaths get
+// the HTML policy with a fresh nonce, available to handlers via CSPNonce.
+func SecurityHeaders(cfg *config.SecurityHeadersConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !cfg.Enabled {
+			return next
+		}
+		hsts := hstsValue(cfg)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			if hsts != "" && (r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")) {
+				h.Set("Strict-Transport-Security", hsts)
+			}
+			h.Set("X-Content-Type-Options", "nosniff")
+			setIfNotEmpty(h, "Referrer-Policy", cfg.ReferrerPolicy)
+			setIfNotEmpty(h, "Permissions-Policy", cfg.PermissionsPolicy)
+			setIfNotEmpty(h, "X-Frame-Options", cfg.FrameOptions)
+
+			if slices.Contains(cfg.HTMLPaths, r.URL.Path) {
+				nonce := newCSPNonce()
+				setIfNotEmpty(h, "Content-Security-Policy", strings.ReplaceAll(cfg.HTMLContentSecurityPolicy, "{nonce}", nonce))
+				r = r.WithContext(context.WithValue(r.Context(), cspNonceKey{}, nonce))
+			} else {
+				setIfNotEmpty(h, "Content-Security-Policy", cfg.ContentSecurityPolicy)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func hstsValue(cfg *config.SecurityHeadersConfig) string {
+	if cfg.HSTSMaxAge <= 0 {
+		return ""
+	}
+	v := "max-age=" + strconv.Itoa(cfg.HSTSMaxAge)
+	if cfg.HSTSIncludeSubdomains {
+		v += "; includeSubDomains"
+	}
+	if cfg.HSTSPreload {
+		v += "; preload"
+	}
+	return v
+}
+
+func setIfNotEmpty(h http.Header, name, value string) {
+	if value != "" {
+		h.Set(name, value)
+	}
+}
+
+func newCSPNonce() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
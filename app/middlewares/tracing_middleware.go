@@ -0,0 +1,26 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing opens a server span per request, continuing the caller's trace when
+// it sends a W3C traceparent header. Spans are named after the matched route.
+func Tracing(mux *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		tagRoute := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := routePattern(mux, r)
+			trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("http.route", route))
+			next.ServeHTTP(w, r)
+		})
+		return otelhttp.NewHandler(tagRoute, "http.server",
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return r.Method + " " + routePattern(mux, r)
+			}),
+		)
+	}
+}
@@ -0,0 +1,39 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"portfolio-backend/app/responses"
+)
+
+// UnmatchedRoutes serves mux, answering requests that match no route with
+// the JSON error envelope: 405 with the mux's Allow header when the path
+// exists for other methods, 404 otherwise.
+func UnmatchedRoutes(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h, pattern := mux.Handler(r)
+		if pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		// Run the mux's own error handler only to learn its status and Allow header
+		rec := &discardRecorder{header: http.Header{}, status: http.StatusOK}
+		h.ServeHTTP(rec, r)
+		if rec.status == http.StatusMethodNotAllowed {
+			w.Header().Set("Allow", rec.header.Get("Allow"))
+			responses.WriteError(w, r, responses.MethodNotAllowed("Method Not Allowed"))
+			return
+		}
+		responses.WriteError(w, r, responses.NotFound("Not Found"))
+	})
+}
+
+type discardRecorder struct {
+	header http.Header
+	status int
+}
+
+func (d *discardRecorder) Header() http.Header         { return d.header }
+func (d *discardRecorder) Write(b []byte) (int, error) { return len(b), nil }
+func (d *discardRecorder) WriteHeader(status int)      { d.status = status }
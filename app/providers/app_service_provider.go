@@ -6,42 +6,114 @@ import (
 	"portfolio-backend/app/middlewares"
 	"portfolio-backend/config"
 	"portfolio-backend/routes"
+	"portfolio-backend/services/captcha"
+	"portfolio-backend/services/geoip"
+	"portfolio-backend/services/redis"
+	"portfolio-backend/services/spam"
+	"portfolio-backend/services/stepup"
+	"portfolio-backend/services/webhooks"
+)
+
+// Ensure UpstashService implements the stores used by middlewares and services
+var (
+	_ middlewares.RateLimiterService = (*redis.UpstashService)(nil)
+	_ captcha.GuardStore             = (*redis.UpstashService)(nil)
+	_ stepup.Store                   = (*redis.UpstashService)(nil)
+	_ spam.Store                     = (*redis.UpstashService)(nil)
+	_ webhooks.Store                 = (*redis.UpstashService)(nil)
 )
 
 type AppServiceProvider struct {
-	Mux               *http.ServeMux
-	CorsProvider      *CorsProvider
-	MailProvider      *MailProvider
-	EmailController   *api_controllers.EmailController
-	DiscordController *api_controllers.DiscordController
+	Mux                  *http.ServeMux
+	RateLimit            *config.RateLimitingConfig
+	SecurityHeaders      *config.SecurityHeadersConfig
+	CorsProvider         *CorsProvider
+	MailProvider         *MailProvider
+	CaptchaProvider      *CaptchaProvider
+	SpamProvider         *SpamProvider
+	StepUpProvider       *StepUpProvider
+	DiscordProvider      *DiscordProvider
+	NotificationProvider *NotificationProvider
+	WebhookProvider      *WebhookProvider
+	HealthProvider       *HealthProvider
+	EmailController      *api_controllers.EmailController
+	DiscordController    *api_controllers.DiscordController
+	CaptchaController    *api_controllers.CaptchaController
+	SpamController       *api_controllers.SpamController
+	WebhookController    *api_controllers.WebhookController
+	HealthController     *api_controllers.HealthController
 }
 
-func NewAppServiceProvider() *AppServiceProvider {
-	corsConfig := config.LoadCORSConfig()
-	corsProvider := NewCorsProvider(corsConfig)
+// NewAppServiceProvider wires every provider, controller and route from the
+// configuration loaded at boot.
+func NewAppServiceProvider(cfg *config.Config) *AppServiceProvider {
+	corsProvider := NewCorsProvider(cfg.CORS)
 	mux := http.NewServeMux()
-	mailProvider := NewMailProvider()
+	redisService := redis.NewUpstashService()
+	mailProvider := NewMailProvider(cfg.Mail, cfg.App, cfg.EmailValidation)
+	captchaProvider := NewCaptchaProvider(cfg.Captcha, redisService)
+	spamProvider := NewSpamProvider(cfg.Spam, redisService)
+	stepUpProvider := NewStepUpProvider(cfg.StepUp, cfg.Captcha, redisService, mailProvider.MailService)
 
-	emailController := api_controllers.NewEmailController(mailProvider.MailService)
-	discordController := api_controllers.NewDiscordController()
+	discordProvider := NewDiscordProvider(cfg.Discord)
+	webhookProvider := NewWebhookProvider(cfg.Webhooks, redisService)
+	healthProvider := NewHealthProvider(cfg.Health, redisService, mailProvider.MailService)
+	notificationProvider := NewNotificationProvider(cfg.Notification, webhookProvider.Service)
 
+	emailController := api_controllers.NewEmailController(
+		mailProvider.MailService,
+		captchaProvider.Verifier,
+		captchaProvider.Rule("send_email"),
+		spamProvider.Pipeline,
+		spamProvider.Signer,
+		spamProvider.Submissions,
+		stepUpProvider.Service,
+		stepUpProvider.Config.RedirectURL,
+		notificationProvider.Dispatcher,
+		geoip.NewResolver(notificationProvider.Config.GeoIPCountryHeader, notificationProvider.Config.GeoIPLookupURL),
+	)
+	discordController := api_controllers.NewDiscordController(discordProvider.Client, discordProvider.Queue, discordProvider.Targets, discordProvider.Limits, cfg.App.DebugDiscordProxy)
+	captchaController := api_controllers.NewCaptchaController(captchaProvider.Guard)
+	spamController := api_controllers.NewSpamController(spamProvider.Classifier, spamProvider.Submissions)
+	webhookController := api_controllers.NewWebhookController(webhookProvider.Service)
+	healthController := api_controllers.NewHealthController(healthProvider.Checker)
+
+	routes.RegisterHealthRoutes(mux, cfg.App.AdminAPIKey, healthController)
 	routes.RegisterAuthRoutes(mux)
-	routes.RegisterWebRoutes(mux, emailController, discordController)
+	routes.RegisterWebRoutes(mux, cfg.API, cfg.RateLimit, redisService, emailController, discordController)
+	routes.RegisterAdminRoutes(mux, cfg.App.AdminAPIKey, captchaController, spamController, webhookController)
+	routes.RegisterMetricsRoutes(mux, cfg.Metrics)
 
 	return &AppServiceProvider{
-		Mux:               mux,
-		CorsProvider:      corsProvider,
-		MailProvider:      mailProvider,
-		EmailController:   emailController,
-		DiscordController: discordController,
+		Mux:                  mux,
+		RateLimit:            cfg.RateLimit,
+		SecurityHeaders:      cfg.SecurityHeaders,
+		CorsProvider:         corsProvider,
+		MailProvider:         mailProvider,
+		CaptchaProvider:      captchaProvider,
+		SpamProvider:         spamProvider,
+		StepUpProvider:       stepUpProvider,
+		DiscordProvider:      discordProvider,
+		NotificationProvider: notificationProvider,
+		WebhookProvider:      webhookProvider,
+		HealthProvider:       healthProvider,
+		EmailController:      emailController,
+		DiscordController:    discordController,
+		CaptchaController:    captchaController,
+		SpamController:       spamController,
+		WebhookController:    webhookController,
+		HealthController:     healthController,
 	}
 }
 
 func (asp *AppServiceProvider) Handler() http.Handler {
-	// Wrap the mux with the global rate limiter, then adapt to HandlerFunc for CORS
-	return asp.CorsProvider.Handler(
-		func(w http.ResponseWriter, r *http.Request) {
-			middlewares.GlobalRateLimiter(asp.Mux).ServeHTTP(w, r)
-		},
-	)
+	// Wrap the mux with the global rate limiter, then adapt to HandlerFunc for CORS.
+	// Requests matching no route get a JSON 404, or 405 with Allow
+	limited := middlewares.GlobalRateLimiter(asp.RateLimit, middlewares.UnmatchedRoutes(asp.Mux), routes.HealthPaths...)
+	cors := asp.CorsProvider.Handler(limited.ServeHTTP)
+	// The server span wraps everything; request IDs come next so every
+	// response and log line carries one. Security headers go on before CORS
+	// so rejected preflights and errors carry them too
+	secured := middlewares.SecurityHeaders(asp.SecurityHeaders)(cors)
+	return middlewares.Tracing(asp.Mux)(middlewares.RequestID(asp.Mux)(middlewares.Metrics(secured)))
 }
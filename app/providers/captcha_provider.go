@@ -0,0 +1,48 @@
+package providers
+
+import (
+	"log"
+	"log/slog"
+
+	"portfolio-backend/config"
+	"portfolio-backend/services/captcha"
+)
+
+type CaptchaProvider struct {
+	Config   *config.CaptchaConfig
+	Guard    *captcha.GuardedVerifier
+	Verifier captcha.Verifier
+}
+
+func NewCaptchaProvider(cfg *config.CaptchaConfig, store captcha.GuardStore) *CaptchaProvider {
+	var verifier captcha.Verifier
+	if cfg.TestMode {
+		slog.Warn("Captcha test mode enabled: only dummy tokens are accepted")
+		verifier = captcha.NewTestVerifier(cfg.Provider, cfg.TestTokens)
+	} else {
+		v, err := captcha.New(cfg.Provider, cfg.SecretKey)
+		if err != nil {
+			log.Fatalf("Failed to initialize captcha verifier: %v", err)
+		}
+		verifier = v
+	}
+
+	guard := captcha.NewGuardedVerifier(verifier, store, guardOptions(cfg))
+	return &CaptchaProvider{Config: cfg, Guard: guard, Verifier: guard}
+}
+
+// guardOptions are the hostname, token age and replay checks applied to
+// every captcha token, including step-up challenges.
+func guardOptions(cfg *config.CaptchaConfig) captcha.GuardOptions {
+	return captcha.GuardOptions{
+		AllowedHostnames: cfg.AllowedHostnames,
+		MaxTokenAge:      cfg.MaxTokenAge,
+		ReplayTTL:        cfg.ReplayTTL,
+	}
+}
+
+// Rule returns the captcha rule configured for a named route.
+func (cp *CaptchaProvider) Rule(route string) captcha.Rule {
+	rc := cp.Config.Route(route)
+	return captcha.Rule{Action: rc.Action, MinScore: rc.MinScore}
+}
@@ -1,40 +1,147 @@
 package providers
 
 import (
+	"log"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
+	"portfolio-backend/app/responses"
 	"portfolio-backend/config"
 )
 
 type CorsProvider struct {
-	config *config.CORSConfig
+	config         *config.CORSConfig
+	paths          []*regexp.Regexp
+	originPatterns []*regexp.Regexp
 }
 
 func NewCorsProvider(cfg *config.CORSConfig) *CorsProvider {
-	return &CorsProvider{config: cfg}
+	originPatterns, err := cfg.OriginMatchers()
+	if err != nil {
+		log.Fatalf("Invalid CORS configuration: %v", err)
+	}
+	if cfg.SupportsCredentials && slices.Contains(cfg.AllowedOrigins, "*") {
+		slog.Warn("CORS allows credentials from any origin: every site can make authenticated requests")
+	}
+
+	paths := make([]*regexp.Regexp, 0, len(cfg.Paths))
+	for _, p := range cfg.Paths {
+		paths = append(paths, compilePathPattern(p))
+	}
+	return &CorsProvider{config: cfg, paths: paths, originPatterns: originPatterns}
 }
 
+// compilePathPattern turns a Paths entry such as "api/*" into an anchored
+// regexp; "*" matches any run of characters, slashes included.
+func compilePathPattern(p string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(strings.TrimPrefix(p, "/"))
+	return regexp.MustCompile("^" + strings.ReplaceAll(quoted, `\*`, ".*") + "$")
+}
+
+// appliesTo reports whether the request path is covered by Paths.
+func (cp *CorsProvider) appliesTo(path string) bool {
+	path = strings.TrimPrefix(path, "/")
+	for _, p := range cp.paths {
+		if p.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedOrigin matches the origin's scheme and host (with port) against the
+// exact origins first, then the compiled patterns. Origins that are not a
+// bare scheme://host[:port], such as "null", are never allowed.
 func (cp *CorsProvider) allowedOrigin(origin string) bool {
-	if origin == "" {
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" || u.RawQuery != "" || u.User != nil {
 		return false
 	}
+	normalized := strings.ToLower(u.Scheme + "://" + u.Host)
+
 	for _, o := range cp.config.AllowedOrigins {
-		if strings.EqualFold(o, origin) {
+		if o == "*" || strings.EqualFold(strings.TrimSuffix(o, "/"), normalized) {
 			return true
 		}
 	}
-	for _, p := range cp.config.AllowedOriginsPatterns {
-		if p != "" && strings.Contains(origin, p) {
+	for _, p := range cp.originPatterns {
+		if p.MatchString(normalized) {
 			return true
 		}
 	}
 	return false
 }
 
+var defaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+
+var headerNamePattern = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
+func (cp *CorsProvider) allowedMethod(method string) bool {
+	methods := cp.config.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	for _, m := range methods {
+		if m == "*" || strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// credentialedHeaders are allowed for CORS_ALLOWED_HEADERS=* when
+// credentials are supported, since browsers ignore a literal "*" then.
+var credentialedHeaders = []string{"Accept", "Authorization", "Content-Type", "X-API-KEY", "X-Request-ID"}
+
+// allowedHeaders returns the value for Access-Control-Allow-Headers, or
+// false when a requested header is not allowed. AllowedHeaders "*" is sent
+// as a literal "*", or as credentialedHeaders for credentialed requests.
+func (cp *CorsProvider) allowedHeaders(requested string) (string, bool) {
+	allowed := cp.config.AllowedHeaders
+	wildcard := slices.Contains(allowed, "*")
+	if wildcard && cp.config.SupportsCredentials {
+		allowed, wildcard = credentialedHeaders, false
+	}
+
+	for _, name := range strings.Split(requested, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if !headerNamePattern.MatchString(name) {
+			return "", false
+		}
+		if !wildcard && !slices.ContainsFunc(allowed, func(h string) bool { return strings.EqualFold(h, name) }) {
+			return "", false
+		}
+	}
+
+	if wildcard {
+		return "*", true
+	}
+	return strings.Join(allowed, ", "), true
+}
+
+// rejectPreflight answers a preflight the policy does not allow. The
+// response carries no Access-Control-Allow-* headers, so the browser blocks
+// the actual request.
+func rejectPreflight(w http.ResponseWriter, r *http.Request, origin, reason string) {
+	slog.InfoContext(r.Context(), "CORS preflight rejected", "origin", origin, "reason", reason)
+	responses.WriteError(w, r, responses.Forbidden("CORS preflight rejected: "+reason))
+}
+
 func (cp *CorsProvider) Handler(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		// paths outside CORS_PATHS never get CORS headers
+		if !cp.appliesTo(r.URL.Path) {
+			next(w, r)
+			return
+		}
+
 		origin := r.Header.Get("Origin")
 		// not a cross-origin request -> continue
 		if origin == "" {
@@ -42,53 +149,74 @@ func (cp *CorsProvider) Handler(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
+		// The answer depends on these request headers, so shared caches
+		// must key on them too
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if preflight {
+			w.Header().Add("Vary", "Origin, Access-Control-Request-Method, Access-Control-Request-Headers, Access-Control-Request-Private-Network")
+		} else {
+			w.Header().Add("Vary", "Origin")
+		}
+
 		if !cp.allowedOrigin(origin) {
+			if preflight {
+				rejectPreflight(w, r, origin, "origin not allowed")
+				return
+			}
 			// origin not allowed: do not set CORS headers (browser will block)
 			next(w, r)
 			return
 		}
 
-		// Echo the origin (required when not using "*")
-		w.Header().Set("Access-Control-Allow-Origin", origin)
-		w.Header().Add("Vary", "Origin")
+		if !preflight {
+			// Echo the origin (required when not using "*")
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if cp.config.SupportsCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+			if len(cp.config.ExposedHeaders) > 0 {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(cp.config.ExposedHeaders, ", "))
+			}
+			next(w, r)
+			return
+		}
 
+		method := r.Header.Get("Access-Control-Request-Method")
+		if !cp.allowedMethod(method) {
+			rejectPreflight(w, r, origin, "method "+method+" not allowed")
+			return
+		}
+		headers, ok := cp.allowedHeaders(r.Header.Get("Access-Control-Request-Headers"))
+		if !ok {
+			rejectPreflight(w, r, origin, "request headers not allowed")
+			return
+		}
+		// Private Network Access: public sites reaching a private address
+		// must be explicitly allowed
+		privateNetwork := r.Header.Get("Access-Control-Request-Private-Network") == "true"
+		if privateNetwork && !cp.config.AllowPrivateNetwork {
+			rejectPreflight(w, r, origin, "private network access not allowed")
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
 		if cp.config.SupportsCredentials {
 			w.Header().Set("Access-Control-Allow-Credentials", "true")
 		}
-
-		// Methods
-		if len(cp.config.AllowedMethods) > 0 {
+		if len(cp.config.AllowedMethods) > 0 && !slices.Contains(cp.config.AllowedMethods, "*") {
 			w.Header().Set("Access-Control-Allow-Methods", strings.Join(cp.config.AllowedMethods, ", "))
 		} else {
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Methods", method)
 		}
-
-		// Headers
-		if len(cp.config.AllowedHeaders) == 1 && cp.config.AllowedHeaders[0] == "*" {
-			reqHeaders := r.Header.Get("Access-Control-Request-Headers")
-			if reqHeaders == "" {
-				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, X-Timezone")
-			} else {
-				w.Header().Set("Access-Control-Allow-Headers", reqHeaders)
-			}
-		} else if len(cp.config.AllowedHeaders) > 0 {
-			w.Header().Set("Access-Control-Allow-Headers", strings.Join(cp.config.AllowedHeaders, ", "))
+		if headers != "" {
+			w.Header().Set("Access-Control-Allow-Headers", headers)
 		}
-
-		if len(cp.config.ExposedHeaders) > 0 {
-			w.Header().Set("Access-Control-Expose-Headers", strings.Join(cp.config.ExposedHeaders, ", "))
+		if privateNetwork {
+			w.Header().Set("Access-Control-Allow-Private-Network", "true")
 		}
-
 		if cp.config.MaxAge > 0 {
 			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(cp.config.MaxAge))
 		}
-
-		// Preflight
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusNoContent)
-			return
-		}
-
-		next(w, r)
+		w.WriteHeader(http.StatusNoContent)
 	}
 }
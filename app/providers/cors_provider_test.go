@@ -0,0 +1,35 @@
+package providers
+
+import (
+	"testing"
+
+	"portfolio-backend/config"
+)
+
+func TestAllowedHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		allowed     []string
+		credentials bool
+		requested   string
+		want        string
+		wantOK      bool
+	}{
+		{"wildcard answers *", []string{"*"}, false, "X-Custom, Content-Type", "*", true},
+		{"wildcard without request", []string{"*"}, false, "", "*", true},
+		{"wildcard rejects invalid names", []string{"*"}, false, "X Bad", "", false},
+		{"credentialed wildcard answers the fixed list", []string{"*"}, true, "content-type", "Accept, Authorization, Content-Type, X-API-KEY, X-Request-ID", true},
+		{"credentialed wildcard rejects other headers", []string{"*"}, true, "X-Custom", "", false},
+		{"configured list", []string{"Content-Type"}, false, "content-type", "Content-Type", true},
+		{"configured list rejects other headers", []string{"Content-Type"}, true, "X-API-KEY", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cp := &CorsProvider{config: &config.CORSConfig{AllowedHeaders: tt.allowed, SupportsCredentials: tt.credentials}}
+			got, ok := cp.allowedHeaders(tt.requested)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("allowedHeaders(%q) = %q, %v, want %q, %v", tt.requested, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
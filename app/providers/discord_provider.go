@@ -0,0 +1,63 @@
+package providers
+
+import (
+	"log"
+	"log/slog"
+
+	"portfolio-backend/config"
+	"portfolio-backend/services/discord"
+)
+
+type DiscordProvider struct {
+	Config  *config.DiscordConfig
+	Client  *discord.Client
+	Queue   *discord.Queue
+	Targets *discord.Targets
+	Limits  discord.UploadLimits
+}
+
+func NewDiscordProvider(cfg *config.DiscordConfig) *DiscordProvider {
+	client := discord.NewClient("")
+	queue := discord.NewQueue(client, cfg.QueueSize, cfg.MaxAttempts, cfg.QueueMaxBytes, cfg.QueueJobBytes)
+	queue.Start()
+
+	targets := []*discord.Target{}
+	for _, tc := range cfg.Targets {
+		target := &discord.Target{
+			Name:       tc.Name,
+			WebhookURL: tc.WebhookURL,
+			APIKeys:    tc.APIKeys,
+			RateLimit:  tc.RateLimit,
+			Burst:      tc.Burst,
+		}
+		if tc.TemplatePath != "" {
+			tmpl, err := discord.LoadTemplate(tc.TemplatePath)
+			if err != nil {
+				log.Fatalf("Failed to load Discord template for target %s: %v", tc.Name, err)
+			}
+			target.Template = tmpl
+		}
+		if tc.Name != "default" {
+			if tc.WebhookURL == "" {
+				log.Fatalf("Discord target %s has no webhook URL", tc.Name)
+			}
+			if len(tc.APIKeys) == 0 {
+				slog.Warn("Discord target has no API keys: anyone can post to it", "target", tc.Name)
+			}
+		}
+		targets = append(targets, target)
+	}
+
+	maxFiles := cfg.MaxFiles
+	if maxFiles <= 0 || maxFiles > discord.MaxFiles {
+		maxFiles = discord.MaxFiles
+	}
+	limits := discord.UploadLimits{
+		MaxBodyBytes: cfg.MaxBodyBytes,
+		MaxFileBytes: cfg.MaxFileBytes,
+		MaxFiles:     maxFiles,
+		AllowedTypes: cfg.AllowedFileTypes,
+	}
+
+	return &DiscordProvider{Config: cfg, Client: client, Queue: queue, Targets: discord.NewTargets(targets...), Limits: limits}
+}
@@ -0,0 +1,28 @@
+package providers
+
+import (
+	"context"
+
+	"portfolio-backend/config"
+	"portfolio-backend/services/email"
+	"portfolio-backend/services/health"
+	"portfolio-backend/services/redis"
+)
+
+type HealthProvider struct {
+	Config  *config.HealthConfig
+	Checker *health.Checker
+}
+
+func NewHealthProvider(cfg *config.HealthConfig, redisService *redis.UpstashService, mailService *email.MailService) *HealthProvider {
+	checker := health.NewChecker(cfg.ProbeTimeout, cfg.CacheTTL)
+	checker.Register("redis", redisService.Ping)
+	checker.Register("templates", func(context.Context) error {
+		return mailService.MailRendererService.CheckTemplates()
+	})
+	if cfg.CheckSMTP {
+		checker.Register("smtp", mailService.PingSMTP)
+	}
+
+	return &HealthProvider{Config: cfg, Checker: checker}
+}
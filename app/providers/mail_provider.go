@@ -2,19 +2,23 @@ package providers
 
 import (
 	"log"
+
+	"portfolio-backend/config"
 	"portfolio-backend/services/email"
+	validation_email "portfolio-backend/services/validation/email"
 )
 
 type MailProvider struct {
 	MailService *email.MailService
 }
 
-func NewMailProvider() *MailProvider {
+func NewMailProvider(cfg *config.MailConfig, appCfg *config.AppConfig, validationCfg *config.EmailValidationConfig) *MailProvider {
 	mailRendererService, err := email.NewMailRendererService()
 	if err != nil {
 		log.Fatalf("Failed to initialize mail renderer service: %v", err)
 	}
-	mailService := email.NewMailService(mailRendererService)
+	validation_email.Configure(validationCfg)
+	mailService := email.NewMailService(mailRendererService, cfg, appCfg.Name, appCfg.URL)
 	return &MailProvider{
 		MailService: mailService,
 	}
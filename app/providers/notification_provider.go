@@ -0,0 +1,49 @@
+package providers
+
+import (
+	"log/slog"
+	"slices"
+
+	"portfolio-backend/config"
+	"portfolio-backend/services/notification"
+	"portfolio-backend/services/webhooks"
+)
+
+type NotificationProvider struct {
+	Config     *config.NotificationConfig
+	Dispatcher *notification.Dispatcher
+}
+
+// NewNotificationProvider registers the configured channels. Webhook
+// subscriptions receive every event type and filter by their own event list.
+func NewNotificationProvider(cfg *config.NotificationConfig, subscriptions *webhooks.Service) *NotificationProvider {
+	dispatcher := notification.NewDispatcher()
+	if cfg.DiscordWebhookURL != "" {
+		dispatcher.Register(notification.NewDiscordNotifier(cfg.DiscordWebhookURL))
+	}
+	if cfg.SlackWebhookURL != "" {
+		dispatcher.Register(notification.NewSlackNotifier(cfg.SlackWebhookURL))
+	}
+	if cfg.TelegramBotToken != "" && cfg.TelegramChatID != "" {
+		dispatcher.Register(notification.NewTelegramNotifier(cfg.TelegramBotToken, cfg.TelegramChatID))
+	}
+	if cfg.WebhookURL != "" {
+		dispatcher.Register(notification.NewWebhookNotifier(cfg.WebhookURL, cfg.WebhookSecret))
+	}
+
+	if len(subscriptions.Subscriptions) > 0 {
+		dispatcher.Register(subscriptions)
+	}
+
+	for _, event := range notification.EventTypes {
+		channels := cfg.Routes[event]
+		if len(subscriptions.Subscriptions) > 0 {
+			channels = append(slices.Clone(channels), subscriptions.Name())
+		}
+		for _, channel := range dispatcher.Route(event, channels...) {
+			slog.Warn("Notification route names a channel that is not configured: skipping it", "event", event, "channel", channel)
+		}
+	}
+
+	return &NotificationProvider{Config: cfg, Dispatcher: dispatcher}
+}
@@ -0,0 +1,74 @@
+package providers
+
+import (
+	"log"
+	"log/slog"
+	"unicode"
+
+	"portfolio-backend/config"
+	"portfolio-backend/services/classifier"
+	"portfolio-backend/services/spam"
+)
+
+type SpamProvider struct {
+	Config      *config.SpamConfig
+	Pipeline    *spam.Pipeline
+	Signer      *spam.FormTokenSigner // nil when SPAM_FORM_TOKEN_SECRET is not set
+	Classifier  *classifier.Classifier
+	Submissions *spam.SubmissionLog
+}
+
+func NewSpamProvider(cfg *config.SpamConfig, store spam.Store) *SpamProvider {
+	scripts := []*unicode.RangeTable{}
+	for _, name := range cfg.AllowedScripts {
+		table, ok := unicode.Scripts[name]
+		if !ok {
+			log.Fatalf("Unknown unicode script in SPAM_ALLOWED_SCRIPTS: %s", name)
+		}
+		scripts = append(scripts, table)
+	}
+
+	pipeline := spam.NewPipeline(cfg.FlagThreshold, cfg.RejectThreshold,
+		spam.HoneypotSignal{Score: cfg.HoneypotScore},
+		spam.LinkSignal{MaxLinks: cfg.MaxLinks, ScorePerLink: cfg.LinkScore},
+		spam.KeywordSignal{Keywords: cfg.Keywords, ScorePerKeyword: cfg.KeywordScore},
+		spam.CharsetSignal{
+			AllowedScripts:  scripts,
+			MaxForeignRatio: cfg.MaxForeignRatio,
+			ForeignScore:    cfg.ForeignScore,
+			InvisibleScore:  cfg.InvisibleScore,
+		},
+	)
+
+	bayes, err := classifier.Load(cfg.ModelPath, cfg.ModelMinDocs)
+	if err != nil {
+		log.Fatalf("Failed to load spam model: %v", err)
+	}
+	pipeline.Use(spam.ClassifierSignal{
+		Classifier: bayes,
+		Threshold:  cfg.ClassifierThreshold,
+		Score:      cfg.ClassifierScore,
+	})
+
+	var signer *spam.FormTokenSigner
+	if cfg.FormTokenSecret != "" {
+		signer = spam.NewFormTokenSigner(cfg.FormTokenSecret)
+		pipeline.Use(spam.TimingSignal{
+			Signer:       signer,
+			MinFillTime:  cfg.MinFillTime,
+			MaxAge:       cfg.MaxFormAge,
+			TooFastScore: cfg.TooFastScore,
+			InvalidScore: cfg.InvalidTokenScore,
+		})
+	} else {
+		slog.Warn("SPAM_FORM_TOKEN_SECRET not set: form timing check disabled")
+	}
+
+	return &SpamProvider{
+		Config:      cfg,
+		Pipeline:    pipeline,
+		Signer:      signer,
+		Classifier:  bayes,
+		Submissions: spam.NewSubmissionLog(store, cfg.SubmissionTTL),
+	}
+}
@@ -0,0 +1,47 @@
+package providers
+
+import (
+	"log"
+
+	"portfolio-backend/config"
+	"portfolio-backend/services/captcha"
+	"portfolio-backend/services/email"
+	"portfolio-backend/services/redis"
+	"portfolio-backend/services/stepup"
+)
+
+type StepUpProvider struct {
+	Config  *config.StepUpConfig
+	Service *stepup.Service
+}
+
+// NewStepUpProvider returns a provider whose Service is nil when step-up is disabled.
+func NewStepUpProvider(cfg *config.StepUpConfig, captchaCfg *config.CaptchaConfig, store *redis.UpstashService, mailService *email.MailService) *StepUpProvider {
+	if cfg.MinScore <= 0 {
+		return &StepUpProvider{Config: cfg}
+	}
+
+	var verifier captcha.Verifier
+	switch {
+	case captchaCfg.TestMode:
+		verifier = captcha.NewTestVerifier(cfg.CaptchaProvider, captchaCfg.TestTokens)
+	case cfg.CaptchaSecretKey != "":
+		v, err := captcha.New(cfg.CaptchaProvider, cfg.CaptchaSecretKey)
+		if err != nil {
+			log.Fatalf("Failed to initialize step-up captcha verifier: %v", err)
+		}
+		verifier = v
+	}
+	if verifier != nil {
+		// Step-up tokens get the same hostname, age and replay checks as the primary captcha
+		verifier = captcha.NewGuardedVerifier(verifier, store, guardOptions(captchaCfg))
+	}
+
+	var mailer stepup.ConfirmationMailer
+	if cfg.EmailConfirmation {
+		mailer = mailService
+	}
+
+	service := stepup.NewService(store, cfg.TTL, cfg.MinScore, verifier, mailer, cfg.ConfirmURL, cfg.ConfirmPerHour)
+	return &StepUpProvider{Config: cfg, Service: service}
+}
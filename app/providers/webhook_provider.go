@@ -0,0 +1,51 @@
+package providers
+
+import (
+	"encoding/json"
+	"log"
+	"net/url"
+	"os"
+
+	"portfolio-backend/config"
+	"portfolio-backend/services/webhooks"
+)
+
+type WebhookProvider struct {
+	Config  *config.WebhookConfig
+	Service *webhooks.Service
+}
+
+func NewWebhookProvider(cfg *config.WebhookConfig, store webhooks.Store) *WebhookProvider {
+	raw := []byte(cfg.Subscriptions)
+	if len(raw) == 0 && cfg.SubscriptionsFile != "" {
+		data, err := os.ReadFile(cfg.SubscriptionsFile)
+		if err != nil {
+			log.Fatalf("Failed to read WEBHOOK_SUBSCRIPTIONS_FILE: %v", err)
+		}
+		raw = data
+	}
+
+	subs := []webhooks.Subscription{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &subs); err != nil {
+			log.Fatalf("Invalid webhook subscriptions: %v", err)
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, sub := range subs {
+		if sub.ID == "" || seen[sub.ID] {
+			log.Fatalf("Webhook subscriptions need a unique id: %q", sub.ID)
+		}
+		seen[sub.ID] = true
+		if u, err := url.Parse(sub.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			log.Fatalf("Webhook subscription %s has an invalid url", sub.ID)
+		}
+		if sub.Secret == "" {
+			log.Fatalf("Webhook subscription %s needs a secret", sub.ID)
+		}
+	}
+
+	service := webhooks.NewService(subs, store, cfg.MaxAttempts, cfg.BaseBackoff, cfg.LogTTL)
+	return &WebhookProvider{Config: cfg, Service: service}
+}
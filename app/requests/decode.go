@@ -0,0 +1,125 @@
+package requests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"portfolio-backend/app/responses"
+	"portfolio-backend/services/validation"
+)
+
+// DefaultMaxBodyBytes is the most DecodeJSON and ReadBody will read on
+// routes that set no limit with the MaxBodyBytes middleware.
+const DefaultMaxBodyBytes = 1 << 20
+
+type bodyLimitKey struct{}
+
+// WithBodyLimit records that the route already limits the request body, so
+// the decoders leave it alone instead of applying DefaultMaxBodyBytes.
+func WithBodyLimit(ctx context.Context, n int64) context.Context {
+	return context.WithValue(ctx, bodyLimitKey{}, n)
+}
+
+// limitedBody returns the request body, capped at DefaultMaxBodyBytes
+// unless the route set its own limit.
+func limitedBody(w http.ResponseWriter, r *http.Request) io.Reader {
+	if _, ok := r.Context().Value(bodyLimitKey{}).(int64); ok {
+		return r.Body
+	}
+	return http.MaxBytesReader(w, r.Body, DefaultMaxBodyBytes)
+}
+
+// DecodeJSON strictly decodes a JSON request body into dst: the Content-Type
+// must be JSON (415), the body must fit the route limit (413) and contain a
+// single object without unknown fields (400). Struct fields are then checked
+// against their `validate` tags, failing with validation.Errors (422).
+func DecodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	if err := RequireContentType(r, "application/json"); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(limitedBody(w, r))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		if err == nil {
+			return responses.BadRequest("Request body must contain a single JSON object")
+		}
+		return decodeError(err)
+	}
+
+	if reflect.Indirect(reflect.ValueOf(dst)).Kind() == reflect.Struct {
+		return validate(r, dst)
+	}
+	return nil
+}
+
+// validate checks dst against its `validate` tags. Anything other than
+// validation.Errors is a broken tag on our side: it is logged, and the
+// client gets a generic 500.
+func validate(r *http.Request, dst interface{}) error {
+	err := validation.Struct(dst)
+	var vErrs validation.Errors
+	if err == nil || errors.As(err, &vErrs) {
+		return err
+	}
+	slog.ErrorContext(r.Context(), "request validation failed", "type", fmt.Sprintf("%T", dst), "error", err)
+	return responses.Internal("Internal Server Error")
+}
+
+// ReadBody reads a raw request body within the route limit.
+func ReadBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(limitedBody(w, r))
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	if len(body) == 0 {
+		return nil, responses.BadRequest("Request body is empty")
+	}
+	return body, nil
+}
+
+// RequireContentType answers 415 unless the request media type is one of
+// allowed. Structured suffixes such as application/problem+json count as JSON.
+func RequireContentType(r *http.Request, allowed ...string) error {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err == nil {
+		for _, a := range allowed {
+			if mediaType == a || (a == "application/json" && strings.HasSuffix(mediaType, "+json")) {
+				return nil
+			}
+		}
+	}
+	return responses.NewError(http.StatusUnsupportedMediaType, responses.CodeUnsupportedMediaType,
+		"Content-Type must be "+strings.Join(allowed, " or "))
+}
+
+func decodeError(err error) error {
+	var tooLarge *http.MaxBytesError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &tooLarge):
+		return responses.FromError(err)
+	case errors.Is(err, io.EOF):
+		return responses.BadRequest("Request body is empty")
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.As(err, &syntaxErr):
+		return responses.BadRequest("Request body contains malformed JSON")
+	case errors.As(err, &typeErr):
+		return responses.BadRequest(fmt.Sprintf("Field %q must be of type %s", typeErr.Field, typeErr.Type))
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		return responses.BadRequest("Unknown field " + strings.TrimPrefix(err.Error(), "json: unknown field "))
+	default:
+		return responses.BadRequest("Invalid request body")
+	}
+}
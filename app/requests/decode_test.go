@@ -0,0 +1,115 @@
+package requests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"portfolio-backend/app/responses"
+)
+
+type decodeTarget struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	large := `{"name":"` + strings.Repeat("a", DefaultMaxBodyBytes) + `"}`
+
+	tests := []struct {
+		name       string
+		body       string
+		limit      int64 // route limit; 0 leaves DefaultMaxBodyBytes
+		wantStatus int   // 0 for success
+		wantCode   string
+	}{
+		{name: "valid", body: `{"name":"Ada"}`},
+		{name: "over the default limit", body: large, wantStatus: http.StatusRequestEntityTooLarge, wantCode: responses.CodePayloadTooLarge},
+		{name: "route limit above the default", body: large, limit: 2 * DefaultMaxBodyBytes},
+		{name: "over the route limit", body: `{"name":"Ada Lovelace"}`, limit: 8, wantStatus: http.StatusRequestEntityTooLarge, wantCode: responses.CodePayloadTooLarge},
+		{name: "unknown field", body: `{"name":"Ada","admin":true}`, wantStatus: http.StatusBadRequest, wantCode: responses.CodeBadRequest},
+		{name: "trailing object", body: `{"name":"Ada"}{"name":"Bob"}`, wantStatus: http.StatusBadRequest, wantCode: responses.CodeBadRequest},
+		{name: "trailing garbage", body: `{"name":"Ada"} x`, wantStatus: http.StatusBadRequest, wantCode: responses.CodeBadRequest},
+		{name: "empty body", body: ``, wantStatus: http.StatusBadRequest, wantCode: responses.CodeBadRequest},
+		{name: "fails validation", body: `{"name":" "}`, wantStatus: http.StatusUnprocessableEntity, wantCode: responses.CodeValidationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/json")
+			if tt.limit > 0 {
+				r = r.WithContext(WithBodyLimit(r.Context(), tt.limit))
+				r.Body = http.MaxBytesReader(w, r.Body, tt.limit)
+			}
+
+			var dst decodeTarget
+			err := DecodeJSON(w, r, &dst)
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("DecodeJSON error = %v", err)
+				}
+				return
+			}
+			e := responses.FromError(err)
+			if e.Status != tt.wantStatus || e.Code != tt.wantCode {
+				t.Errorf("DecodeJSON error = %d %s (%v), want %d %s", e.Status, e.Code, err, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONRequiresJSON(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Ada"}`))
+	r.Header.Set("Content-Type", "text/plain")
+	var dst decodeTarget
+	if e := responses.FromError(DecodeJSON(httptest.NewRecorder(), r, &dst)); e.Status != http.StatusUnsupportedMediaType {
+		t.Errorf("status = %d, want %d", e.Status, http.StatusUnsupportedMediaType)
+	}
+}
+
+func TestDecodeJSONFieldErrorResponse(t *testing.T) {
+	var dst struct {
+		Name  string `json:"name" validate:"required"`
+		Email string `json:"email" validate:"required,email"`
+	}
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"email":"nope"}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	responses.WriteError(w, r, DecodeJSON(w, r, &dst))
+
+	if w.Code != http.StatusUnprocessableEntity || w.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("response = %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	var got responses.Envelope
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := responses.Envelope{
+		Message: "The email field must be a valid email address. (and 1 more error)",
+		Code:    responses.CodeValidationFailed,
+		Errors: map[string][]string{
+			"name":  {"The name field is required."},
+			"email": {"The email field must be a valid email address."},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("body = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeJSONInvalidRule(t *testing.T) {
+	var dst struct {
+		Name string `json:"name" validate:"max=five"`
+	}
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Ada"}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	responses.WriteError(w, r, DecodeJSON(w, r, &dst))
+
+	if w.Code != http.StatusInternalServerError || strings.Contains(w.Body.String(), "five") {
+		t.Errorf("response = %d %s, want a generic 500", w.Code, w.Body)
+	}
+}
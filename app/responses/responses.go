@@ -0,0 +1,154 @@
+package responses
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"portfolio-backend/services/validation"
+)
+
+// Error codes shared with the frontend.
+const (
+	CodeBadRequest           = "bad_request"
+	CodeUnauthorized         = "unauthorized"
+	CodeForbidden            = "forbidden"
+	CodeNotFound             = "not_found"
+	CodeMethodNotAllowed     = "method_not_allowed"
+	CodePayloadTooLarge      = "payload_too_large"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeValidationFailed     = "validation_failed"
+	CodeTooManyRequests      = "too_many_requests"
+	CodeInternal             = "internal_error"
+	CodeBadGateway           = "bad_gateway"
+	CodeServiceUnavailable   = "service_unavailable"
+)
+
+// Error is an application error with the HTTP status it answers. Details
+// carries extra machine-readable context; Fields carries validation messages.
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	Details interface{}
+	Fields  map[string][]string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// WithDetails returns a copy of e carrying details.
+func (e *Error) WithDetails(details interface{}) *Error {
+	c := *e
+	c.Details = details
+	return &c
+}
+
+func NewError(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+func BadRequest(message string) *Error {
+	return NewError(http.StatusBadRequest, CodeBadRequest, message)
+}
+
+func Unauthorized(message string) *Error {
+	return NewError(http.StatusUnauthorized, CodeUnauthorized, message)
+}
+
+func Forbidden(message string) *Error {
+	return NewError(http.StatusForbidden, CodeForbidden, message)
+}
+
+func NotFound(message string) *Error {
+	return NewError(http.StatusNotFound, CodeNotFound, message)
+}
+
+func MethodNotAllowed(message string) *Error {
+	return NewError(http.StatusMethodNotAllowed, CodeMethodNotAllowed, message)
+}
+
+func TooManyRequests(message string) *Error {
+	return NewError(http.StatusTooManyRequests, CodeTooManyRequests, message)
+}
+
+func Internal(message string) *Error {
+	return NewError(http.StatusInternalServerError, CodeInternal, message)
+}
+
+// Envelope is the JSON body of every error response.
+type Envelope struct {
+	Message string              `json:"message"`
+	Code    string              `json:"code"`
+	Errors  map[string][]string `json:"errors,omitempty"`
+	Details interface{}         `json:"details,omitempty"`
+}
+
+// FromError converts err into an *Error: validation failures become 422,
+// exceeded body limits 413, and anything unknown a generic 500 so internal
+// messages never reach clients.
+func FromError(err error) *Error {
+	var appErr *Error
+	var vErrs validation.Errors
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &appErr):
+		return appErr
+	case errors.As(err, &vErrs):
+		return &Error{Status: http.StatusUnprocessableEntity, Code: CodeValidationFailed, Message: vErrs.Message(), Fields: vErrs}
+	case errors.As(err, &tooLarge):
+		return NewError(http.StatusRequestEntityTooLarge, CodePayloadTooLarge, fmt.Sprintf("Request body must be at most %d bytes", tooLarge.Limit))
+	default:
+		return Internal("Internal Server Error")
+	}
+}
+
+// WriteError answers with err, as the JSON envelope unless the client only
+// accepts plain text or HTML, in which case the message is sent as text.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	e := FromError(err)
+	if !wantsJSON(r) {
+		http.Error(w, e.Message, e.Status)
+		return
+	}
+	JSON(w, e.Status, Envelope{Message: e.Message, Code: e.Code, Errors: e.Fields, Details: e.Details})
+}
+
+// JSON writes v as a JSON response with status.
+func JSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Message writes a JSON {"message": ...} success response.
+func Message(w http.ResponseWriter, status int, message string) {
+	JSON(w, status, map[string]string{"message": message})
+}
+
+// wantsJSON reports whether JSON is acceptable to the client. Requests
+// without an Accept header, or accepting */* or any JSON type, get JSON.
+func wantsJSON(r *http.Request) bool {
+	if r == nil {
+		return true
+	}
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return true
+	}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || params["q"] == "0" {
+			continue
+		}
+		if mediaType == "*/*" || mediaType == "application/*" || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+			return true
+		}
+	}
+	return false
+}
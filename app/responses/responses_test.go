@@ -0,0 +1,120 @@
+package responses
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"portfolio-backend/services/validation"
+)
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		accept     string
+		wantStatus int
+		wantType   string
+		wantBody   string
+	}{
+		{"not found", NotFound("No such page"), "", http.StatusNotFound, "application/json",
+			`{"message":"No such page","code":"not_found"}`},
+		{"forbidden", Forbidden("Nope"), "", http.StatusForbidden, "application/json",
+			`{"message":"Nope","code":"forbidden"}`},
+		{"bad request", BadRequest("Bad body"), "", http.StatusBadRequest, "application/json",
+			`{"message":"Bad body","code":"bad_request"}`},
+		{"too many requests", TooManyRequests("Slow down"), "", http.StatusTooManyRequests, "application/json",
+			`{"message":"Slow down","code":"too_many_requests"}`},
+		{"internal", Internal("Internal Server Error"), "", http.StatusInternalServerError, "application/json",
+			`{"message":"Internal Server Error","code":"internal_error"}`},
+		{"method not allowed", MethodNotAllowed("Use POST"), "", http.StatusMethodNotAllowed, "application/json",
+			`{"message":"Use POST","code":"method_not_allowed"}`},
+		{"with details", NewError(http.StatusBadGateway, CodeBadGateway, "Upstream failed").WithDetails(map[string]int{"status": 502}), "", http.StatusBadGateway, "application/json",
+			`{"message":"Upstream failed","code":"bad_gateway","details":{"status":502}}`},
+		{"unknown errors stay internal", errors.New("dial tcp: secret host"), "", http.StatusInternalServerError, "application/json",
+			`{"message":"Internal Server Error","code":"internal_error"}`},
+		{"body too large", &http.MaxBytesError{Limit: 10}, "", http.StatusRequestEntityTooLarge, "application/json",
+			`{"message":"Request body must be at most 10 bytes","code":"payload_too_large"}`},
+		{"validation errors", validation.Errors{"name": {"The name field is required."}}, "", http.StatusUnprocessableEntity, "application/json",
+			`{"message":"The name field is required.","code":"validation_failed","errors":{"name":["The name field is required."]}}`},
+		{"accepts any type", NotFound("No such page"), "*/*", http.StatusNotFound, "application/json",
+			`{"message":"No such page","code":"not_found"}`},
+		{"accepts problem+json", NotFound("No such page"), "application/problem+json", http.StatusNotFound, "application/json",
+			`{"message":"No such page","code":"not_found"}`},
+		{"plain text", NotFound("No such page"), "text/plain", http.StatusNotFound, "text/plain; charset=utf-8",
+			"No such page\n"},
+		{"JSON refused with q=0", Forbidden("Nope"), "application/json;q=0, text/html", http.StatusForbidden, "text/plain; charset=utf-8",
+			"Nope\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			WriteError(w, r, tt.err)
+
+			if w.Code != tt.wantStatus || w.Header().Get("Content-Type") != tt.wantType {
+				t.Errorf("response = %d %q, want %d %q", w.Code, w.Header().Get("Content-Type"), tt.wantStatus, tt.wantType)
+			}
+			assertBody(t, w, tt.wantBody)
+		})
+	}
+}
+
+func TestWithDetailsCopies(t *testing.T) {
+	base := NewError(http.StatusBadGateway, CodeBadGateway, "Upstream failed")
+	base.WithDetails("extra")
+	if base.Details != nil {
+		t.Errorf("WithDetails modified the original: %+v", base)
+	}
+}
+
+func TestSuccessHelpers(t *testing.T) {
+	tests := []struct {
+		name       string
+		write      func(w http.ResponseWriter)
+		wantStatus int
+		wantBody   string
+	}{
+		{"message", func(w http.ResponseWriter) { Message(w, http.StatusCreated, "Email sent") }, http.StatusCreated,
+			`{"message":"Email sent"}`},
+		{"json", func(w http.ResponseWriter) { JSON(w, http.StatusOK, map[string]int{"count": 2}) }, http.StatusOK,
+			`{"count":2}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.write(w)
+			if w.Code != tt.wantStatus || w.Header().Get("Content-Type") != "application/json" || w.Header().Get("X-Content-Type-Options") != "nosniff" {
+				t.Errorf("response = %d %v", w.Code, w.Header())
+			}
+			assertBody(t, w, tt.wantBody)
+		})
+	}
+}
+
+// assertBody compares JSON bodies structurally and anything else verbatim.
+func assertBody(t *testing.T, w *httptest.ResponseRecorder, want string) {
+	t.Helper()
+	if w.Header().Get("Content-Type") != "application/json" {
+		if w.Body.String() != want {
+			t.Errorf("body = %q, want %q", w.Body, want)
+		}
+		return
+	}
+	var got, wantJSON interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("body %q: %v", w.Body, err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantJSON); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, wantJSON) {
+		t.Errorf("body = %s, want %s", w.Body, want)
+	}
+}
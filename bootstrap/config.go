@@ -0,0 +1,24 @@
+package bootstrap
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"portfolio-backend/config"
+)
+
+// LoadConfig loads and validates the configuration, exiting with every
+// problem listed when it is invalid so misconfigurations surface at deploy
+// time instead of on the first contact form submission.
+func LoadConfig() *config.Config {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid configuration:")
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintln(os.Stderr, "  - "+line)
+		}
+		os.Exit(1)
+	}
+	return cfg
+}
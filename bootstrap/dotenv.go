@@ -0,0 +1,150 @@
+package bootstrap
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// parseDotenv reads NAME=value lines into vars and returns the names in file
+// order. Values are either unquoted (a " #" starts a comment), 'single quoted'
+// (taken literally) or "double quoted" (\n, \t, \" and \\ escapes). Quoted
+// values may span lines. Unquoted and double-quoted values expand ${NAME},
+// ${NAME:-default} and $NAME from the process environment first, then from
+// vars, so a file can build on the layers loaded before it; \$ is a literal $.
+func parseDotenv(data string, vars map[string]string) ([]string, error) {
+	lookup := func(name string) string {
+		if v, ok := os.LookupEnv(name); ok {
+			return v
+		}
+		return vars[name]
+	}
+
+	var names []string
+	lineNo := 0
+	for data != "" {
+		var line string
+		line, data, _ = strings.Cut(data, "\n")
+		lineNo++
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, raw, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		name = strings.TrimSpace(name)
+		if !ok || !envNamePattern.MatchString(name) {
+			return names, fmt.Errorf("line %d: expected NAME=value", lineNo)
+		}
+		raw = strings.TrimLeft(raw, " \t")
+		start := lineNo
+
+		var value string
+		for {
+			if value, ok = scanValue(raw, lookup); ok {
+				break
+			}
+			if data == "" {
+				return names, fmt.Errorf("line %d: unterminated quoted value for %s", start, name)
+			}
+			var next string
+			next, data, _ = strings.Cut(data, "\n")
+			lineNo++
+			raw += "\n" + next
+		}
+
+		vars[name] = value
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// scanValue decodes one raw value. ok is false when a quoted value has not
+// been closed yet.
+func scanValue(raw string, lookup func(string) string) (value string, ok bool) {
+	if raw == "" {
+		return "", true
+	}
+
+	quote := raw[0]
+	if quote == '\'' {
+		end := strings.IndexByte(raw[1:], '\'')
+		if end < 0 {
+			return "", false
+		}
+		return raw[1 : end+1], true
+	}
+	if quote != '"' {
+		quote = 0
+	}
+
+	var b strings.Builder
+	i := 0
+	if quote != 0 {
+		i = 1
+	}
+	for i < len(raw) {
+		c := raw[i]
+		switch {
+		case quote != 0 && c == quote:
+			return b.String(), true
+		case quote == 0 && c == '#' && (i == 0 || raw[i-1] == ' ' || raw[i-1] == '\t'):
+			return strings.TrimSpace(b.String()), true
+		case c == '\\' && i+1 < len(raw):
+			next := raw[i+1]
+			switch {
+			case next == '$':
+				b.WriteByte('$')
+			case quote == 0:
+				// Unquoted values keep backslashes, e.g. Windows paths.
+				b.WriteByte(c)
+				b.WriteByte(next)
+			case next == 'n':
+				b.WriteByte('\n')
+			case next == 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(next)
+			}
+			i += 2
+			continue
+		case c == '$':
+			expanded, n := expandRef(raw[i:], lookup)
+			b.WriteString(expanded)
+			i += n
+			continue
+		default:
+			b.WriteByte(c)
+		}
+		i++
+	}
+	if quote != 0 {
+		return "", false
+	}
+	return strings.TrimSpace(b.String()), true
+}
+
+var bareRefPattern = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// expandRef expands the variable reference at the start of s and returns the
+// number of bytes it used. A $ that starts no reference is kept as is.
+func expandRef(s string, lookup func(string) string) (string, int) {
+	if strings.HasPrefix(s, "${") {
+		end := strings.IndexByte(s, '}')
+		if end < 0 {
+			return "$", 1
+		}
+		name, def, hasDefault := strings.Cut(s[2:end], ":-")
+		if v := lookup(name); v != "" || !hasDefault {
+			return v, end + 1
+		}
+		return def, end + 1
+	}
+	if m := bareRefPattern.FindStringSubmatch(s); m != nil {
+		return lookup(m[1]), len(m[0])
+	}
+	return "$", 1
+}
@@ -0,0 +1,229 @@
+package bootstrap
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseDotenv(t *testing.T) {
+	t.Setenv("DOTENV_TEST_PROCESS", "from-process")
+
+	tests := []struct {
+		name  string
+		data  string
+		vars  map[string]string // values from earlier layers
+		want  map[string]string
+		names []string
+	}{
+		{
+			name:  "unquoted",
+			data:  "A=hello world\n",
+			want:  map[string]string{"A": "hello world"},
+			names: []string{"A"},
+		},
+		{
+			name:  "comments and blank lines",
+			data:  "# comment\n\n  # indented comment\nA=1\n",
+			want:  map[string]string{"A": "1"},
+			names: []string{"A"},
+		},
+		{
+			name: "inline comment needs leading whitespace",
+			data: "A=value # comment\nB=value#not-a-comment\nC=#empty\n",
+			want: map[string]string{"A": "value", "B": "value#not-a-comment", "C": ""},
+		},
+		{
+			name: "export prefix and spaces around =",
+			data: "export A=1\nB = 2\n",
+			want: map[string]string{"A": "1", "B": "2"},
+		},
+		{
+			name: "empty value",
+			data: "A=\nB=''\nC=\"\"\n",
+			want: map[string]string{"A": "", "B": "", "C": ""},
+		},
+		{
+			name: "single quotes are literal",
+			data: `A='$HOME \n # not a comment'` + "\n",
+			want: map[string]string{"A": `$HOME \n # not a comment`},
+		},
+		{
+			name: "double quote escapes",
+			data: `A="line1\nline2\ttab \"quoted\" back\\slash"` + "\n",
+			want: map[string]string{"A": "line1\nline2\ttab \"quoted\" back\\slash"},
+		},
+		{
+			name: "unquoted keeps backslashes",
+			data: `A=C:\path\to` + "\n",
+			want: map[string]string{"A": `C:\path\to`},
+		},
+		{
+			name: "multiline quoted values",
+			data: "A=\"first\nsecond\"\nB='x\ny'\nC=after\n",
+			want: map[string]string{"A": "first\nsecond", "B": "x\ny", "C": "after"},
+		},
+		{
+			name: "expands from the same file",
+			data: "HOST=example.com\nURL=https://${HOST}/path\nBARE=$HOST:8080\n",
+			want: map[string]string{"HOST": "example.com", "URL": "https://example.com/path", "BARE": "example.com:8080"},
+		},
+		{
+			name: "expands from earlier layers",
+			data: "URL=${SERVER_URL}/confirm\n",
+			vars: map[string]string{"SERVER_URL": "http://localhost:8080"},
+			want: map[string]string{"SERVER_URL": "http://localhost:8080", "URL": "http://localhost:8080/confirm"},
+		},
+		{
+			name: "process environment wins over files",
+			data: "DOTENV_TEST_PROCESS=from-file\nA=${DOTENV_TEST_PROCESS}\n",
+			want: map[string]string{"DOTENV_TEST_PROCESS": "from-file", "A": "from-process"},
+		},
+		{
+			name: "defaults",
+			data: "A=${DOTENV_TEST_UNSET:-fallback}\nB=${DOTENV_TEST_PROCESS:-fallback}\nC=${DOTENV_TEST_UNSET}\n",
+			want: map[string]string{"A": "fallback", "B": "from-process", "C": ""},
+		},
+		{
+			name: "escaped and stray dollars",
+			data: "A=\\$HOME\nB=\"cost \\$5\"\nC=5$ ${unclosed\n",
+			want: map[string]string{"A": "$HOME", "B": "cost $5", "C": "5$ ${unclosed"},
+		},
+		{
+			name: "later lines override earlier ones",
+			data: "A=1\nA=2\n",
+			want: map[string]string{"A": "2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := map[string]string{}
+			for k, v := range tt.vars {
+				vars[k] = v
+			}
+			names, err := parseDotenv(tt.data, vars)
+			if err != nil {
+				t.Fatalf("parseDotenv error: %v", err)
+			}
+			if !reflect.DeepEqual(vars, tt.want) {
+				t.Errorf("vars = %q, want %q", vars, tt.want)
+			}
+			if tt.names != nil && !reflect.DeepEqual(names, tt.names) {
+				t.Errorf("names = %q, want %q", names, tt.names)
+			}
+		})
+	}
+}
+
+func TestParseDotenvErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"missing =", "A=1\nNOT_AN_ASSIGNMENT\n", "line 2: expected NAME=value"},
+		{"invalid name", "1A=x\n", "line 1: expected NAME=value"},
+		{"unterminated double quote", "A=1\nB=\"open\nstill open\n", "line 2: unterminated quoted value for B"},
+		{"unterminated single quote", "A='open\n", "line 1: unterminated quoted value for A"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseDotenv(tt.data, map[string]string{})
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("parseDotenv error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadEnvLayers(t *testing.T) {
+	files := map[string]string{
+		".env": "APP_ENV=staging\n" +
+			"DOTENV_TEST_BASE=base\n" +
+			"DOTENV_TEST_ENV=base\n" +
+			"DOTENV_TEST_LOCAL=base\n" +
+			"DOTENV_TEST_REAL=base\n",
+		".env.staging": "DOTENV_TEST_ENV=staging\n" +
+			"DOTENV_TEST_LOCAL=staging\n" +
+			"DOTENV_TEST_REAL=staging\n" +
+			"DOTENV_TEST_EXPANDED=${DOTENV_TEST_BASE}-staging\n",
+		".env.production": "DOTENV_TEST_ENV=production\n",
+		".env.local":      "DOTENV_TEST_LOCAL=local\nDOTENV_TEST_REAL=local\n",
+	}
+
+	tests := []struct {
+		name      string
+		appEnv    string // APP_ENV in the process environment; empty leaves it to .env
+		realEnv   map[string]string
+		want      map[string]string
+		wantFiles []string
+	}{
+		{
+			name: "each layer overrides the one below",
+			want: map[string]string{
+				"DOTENV_TEST_BASE":     "base",
+				"DOTENV_TEST_ENV":      "staging",
+				"DOTENV_TEST_LOCAL":    "local",
+				"DOTENV_TEST_REAL":     "local",
+				"DOTENV_TEST_EXPANDED": "base-staging",
+			},
+			wantFiles: []string{".env", ".env.staging", ".env.local"},
+		},
+		{
+			name:    "real environment wins over every file",
+			realEnv: map[string]string{"DOTENV_TEST_REAL": "real", "DOTENV_TEST_BASE": "real"},
+			want: map[string]string{
+				"DOTENV_TEST_BASE":     "real",
+				"DOTENV_TEST_REAL":     "real",
+				"DOTENV_TEST_EXPANDED": "real-staging",
+			},
+			wantFiles: []string{".env", ".env.staging", ".env.local"},
+		},
+		{
+			name:      "APP_ENV from the environment selects the layer",
+			appEnv:    "production",
+			want:      map[string]string{"DOTENV_TEST_ENV": "production", "DOTENV_TEST_LOCAL": "local"},
+			wantFiles: []string{".env", ".env.production", ".env.local"},
+		},
+		{
+			name:      "local APP_ENV only reads .env.local",
+			appEnv:    "local",
+			want:      map[string]string{"DOTENV_TEST_ENV": "base", "DOTENV_TEST_EXPANDED": ""},
+			wantFiles: []string{".env", ".env.local"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			t.Chdir(dir)
+
+			// t.Setenv restores each variable, so unset ones are unset again afterwards
+			for _, name := range []string{"APP_ENV", "DOTENV_TEST_BASE", "DOTENV_TEST_ENV", "DOTENV_TEST_LOCAL", "DOTENV_TEST_REAL", "DOTENV_TEST_EXPANDED"} {
+				t.Setenv(name, "")
+				os.Unsetenv(name)
+			}
+			if tt.appEnv != "" {
+				t.Setenv("APP_ENV", tt.appEnv)
+			}
+			for name, value := range tt.realEnv {
+				t.Setenv(name, value)
+			}
+
+			env := LoadEnv()
+			if !reflect.DeepEqual(env.Files, tt.wantFiles) {
+				t.Errorf("Files = %q, want %q", env.Files, tt.wantFiles)
+			}
+			for name, want := range tt.want {
+				if got := os.Getenv(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
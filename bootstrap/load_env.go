@@ -1,14 +1,155 @@
 package bootstrap
 
 import (
+	"errors"
+	"io/fs"
 	"log"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
 
-	"github.com/joho/godotenv"
+	"portfolio-backend/config"
 )
 
-func LoadEnv() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Println("No .env file found or failed to load")
+// Env reports what LoadEnv did so it can be logged once logging is set up.
+type Env struct {
+	Files    []string // dotenv files that were read, lowest precedence first
+	warnings []envWarning
+}
+
+type envWarning struct {
+	msg  string
+	args []interface{}
+}
+
+// LoadEnv loads the dotenv layers into the environment, lowest precedence
+// first: .env, .env.{APP_ENV}, .env.local. Later files override earlier ones
+// and variables already set in the process environment always win. APP_ENV
+// itself is read from the environment or .env.
+//
+// A setting named NAME_FILE reads NAME from that file (Docker and Kubernetes
+// secrets), unless NAME is set directly. Unknown keys in the files and
+// deprecated keys anywhere are reported as warnings.
+func LoadEnv() *Env {
+	env := &Env{}
+	vars := map[string]string{}
+	fileKeys := map[string]string{} // key -> file that last set it
+
+	load := func(file string) {
+		raw, err := os.ReadFile(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			return
+		}
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", file, err)
+		}
+		names, err := parseDotenv(string(raw), vars)
+		if err != nil {
+			log.Fatalf("Failed to parse %s: %v", file, err)
+		}
+		for _, name := range names {
+			fileKeys[name] = file
+		}
+		env.Files = append(env.Files, file)
+	}
+
+	load(".env")
+	appEnv, ok := os.LookupEnv("APP_ENV")
+	if !ok {
+		appEnv = vars["APP_ENV"]
+	}
+	for _, file := range envFiles(appEnv) {
+		load(file)
+	}
+
+	for name, value := range vars {
+		if _, set := os.LookupEnv(name); !set {
+			os.Setenv(name, value)
+		}
+	}
+
+	env.loadSecretFiles()
+	env.checkKeys(fileKeys)
+	return env
+}
+
+// envFiles returns the layers above .env for appEnv.
+func envFiles(appEnv string) []string {
+	var files []string
+	if appEnv != "" && appEnv != "local" {
+		files = append(files, ".env."+appEnv)
+	}
+	return append(files, ".env.local")
+}
+
+// loadSecretFiles resolves NAME_FILE settings for known keys.
+func (e *Env) loadSecretFiles() {
+	for _, name := range environNames() {
+		base, ok := strings.CutSuffix(name, "_FILE")
+		if !ok || isKnownKey(name) || !isKnownKey(base) {
+			continue
+		}
+		if os.Getenv(base) != "" {
+			e.warn("Secret file ignored because the setting is set directly", "key", base, "file_key", name)
+			continue
+		}
+		raw, err := os.ReadFile(os.Getenv(name))
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", name, err)
+		}
+		os.Setenv(base, strings.TrimRight(string(raw), "\r\n"))
+	}
+}
+
+// checkKeys warns about keys in the dotenv files that the application does
+// not read (usually typos) and about deprecated keys wherever they are set.
+func (e *Env) checkKeys(fileKeys map[string]string) {
+	names := make([]string, 0, len(fileKeys))
+	for name := range fileKeys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		base, isFile := strings.CutSuffix(name, "_FILE")
+		if !isKnownKey(name) && !(isFile && isKnownKey(base)) {
+			e.warn("Unknown setting", "key", name, "file", fileKeys[name])
+		}
+	}
+
+	for _, name := range environNames() {
+		if k, ok := config.LookupKey(name); ok && k.Deprecated != "" {
+			e.warn("Deprecated setting", "key", name, "use", k.Deprecated)
+		}
+	}
+}
+
+func (e *Env) warn(msg string, args ...interface{}) {
+	e.warnings = append(e.warnings, envWarning{msg, args})
+}
+
+// Log writes the loaded files and any warnings.
+func (e *Env) Log() {
+	if len(e.Files) > 0 {
+		slog.Info("Environment files loaded", "files", e.Files)
+	}
+	for _, w := range e.warnings {
+		slog.Warn(w.msg, w.args...)
+	}
+}
+
+func isKnownKey(name string) bool {
+	_, ok := config.LookupKey(name)
+	return ok
+}
+
+// environNames returns the names of all environment variables, sorted.
+func environNames() []string {
+	var names []string
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	return names
 }
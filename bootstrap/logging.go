@@ -0,0 +1,17 @@
+package bootstrap
+
+import (
+	"log/slog"
+	"os"
+
+	"portfolio-backend/config"
+	"portfolio-backend/services/logging"
+)
+
+// SetupLogging installs the default slog logger configured by LOG_LEVEL and
+// LOG_FORMAT. Messages from the log package are only startup fatals, so
+// they are logged at error level.
+func SetupLogging(cfg *config.LoggingConfig) {
+	slog.SetDefault(logging.New(os.Stdout, cfg.Level, cfg.Format))
+	slog.SetLogLoggerLevel(slog.LevelError)
+}
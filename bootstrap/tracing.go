@@ -0,0 +1,19 @@
+package bootstrap
+
+import (
+	"context"
+	"log"
+
+	"portfolio-backend/config"
+	"portfolio-backend/services/tracing"
+)
+
+// SetupTracing installs the tracer provider configured by OTEL_TRACES_EXPORTER
+// and returns the function that flushes pending spans on shutdown.
+func SetupTracing(cfg *config.TracingConfig) func(context.Context) error {
+	shutdown, err := tracing.Setup(context.Background(), cfg.Exporter, cfg.ServiceName, cfg.SampleRatio)
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+	return shutdown
+}
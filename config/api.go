@@ -0,0 +1,26 @@
+package config
+
+import (
+	"os"
+	"time"
+
+	"portfolio-backend/utils"
+)
+
+// APIConfig controls the unversioned paths that predate /api/v1. They stay
+// served as aliases with Deprecation and Sunset headers until turned off.
+type APIConfig struct {
+	LegacyRoutes bool
+	DeprecatedAt time.Time
+	Sunset       time.Time // zero omits the Sunset header
+}
+
+func LoadAPIConfig() *APIConfig {
+	deprecatedAt, _ := time.Parse(time.DateOnly, utils.GetEnvOrDefault("API_LEGACY_DEPRECATED_AT", "2026-10-19"))
+	sunset, _ := time.Parse(time.DateOnly, envOrDefaultAllowEmpty("API_LEGACY_SUNSET", "2027-04-19"))
+	return &APIConfig{
+		LegacyRoutes: os.Getenv("API_LEGACY_ROUTES") != "false",
+		DeprecatedAt: deprecatedAt,
+		Sunset:       sunset,
+	}
+}
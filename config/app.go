@@ -0,0 +1,35 @@
+package config
+
+import (
+	"os"
+
+	"portfolio-backend/utils"
+)
+
+// AppConfig holds the server-wide settings.
+type AppConfig struct {
+	Name              string
+	Env               string
+	URL               string // public site linked from emails
+	Port              string
+	ServerURL         string // public URL of this backend
+	AdminAPIKey       string // empty disables the admin routes
+	DebugDiscordProxy bool   // echo Discord error bodies to clients
+}
+
+func LoadAppConfig() *AppConfig {
+	// Prefer the PORT that hosting provides; fallback to SERVER_PORT then 8080.
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = utils.GetEnvOrDefault("SERVER_PORT", "8080")
+	}
+	return &AppConfig{
+		Name:              utils.GetEnvOrDefault("APP_NAME", "Portfolio"),
+		Env:               utils.GetEnvOrDefault("APP_ENV", "local"),
+		URL:               utils.GetEnvOrDefault("APP_URL", "https://yourdomain.com"),
+		Port:              port,
+		ServerURL:         utils.GetEnvOrDefault("SERVER_URL", port),
+		AdminAPIKey:       os.Getenv("ADMIN_API_KEY"),
+		DebugDiscordProxy: os.Getenv("DEBUG_DISCORD_PROXY") == "true",
+	}
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"portfolio-backend/utils"
+)
+
+// CaptchaRouteConfig holds the action and score threshold expected for a route.
+type CaptchaRouteConfig struct {
+	Action   string
+	MinScore float64
+}
+
+type CaptchaConfig struct {
+	Provider   string // recaptcha_v3, recaptcha_v2, hcaptcha, turnstile
+	SecretKey  string
+	TestMode   bool
+	TestTokens map[string]float64
+	Routes     map[string]CaptchaRouteConfig
+
+	AllowedHostnames []string      // hostnames the token must have been solved on; empty allows any
+	MaxTokenAge      time.Duration // maximum age of the challenge timestamp; 0 disables the check
+	ReplayTTL        time.Duration // how long used tokens are remembered; 0 disables replay protection
+}
+
+// LoadCaptchaConfig reads captcha settings from env.
+// Per-route settings use CAPTCHA_<ROUTE>_ACTION and CAPTCHA_<ROUTE>_MIN_SCORE,
+// e.g. CAPTCHA_SEND_EMAIL_MIN_SCORE=0.7.
+func LoadCaptchaConfig() *CaptchaConfig {
+	secret := os.Getenv("CAPTCHA_SECRET_KEY")
+	if secret == "" {
+		secret = os.Getenv("RECAPTCHA_SECRET_KEY")
+	}
+
+	routes := map[string]CaptchaRouteConfig{
+		"send_email": {Action: "contact_submit", MinScore: 0.5},
+	}
+	for name, rc := range routes {
+		prefix := "CAPTCHA_" + strings.ToUpper(name) + "_"
+		rc.Action = utils.GetEnvOrDefault(prefix+"ACTION", rc.Action)
+		if v, err := strconv.ParseFloat(os.Getenv(prefix+"MIN_SCORE"), 64); err == nil {
+			rc.MinScore = v
+		}
+		routes[name] = rc
+	}
+
+	return &CaptchaConfig{
+		Provider:   strings.ToLower(utils.GetEnvOrDefault("CAPTCHA_PROVIDER", "recaptcha_v3")),
+		SecretKey:  secret,
+		TestMode:   os.Getenv("CAPTCHA_TEST_MODE") == "true",
+		TestTokens: parseTestTokens(os.Getenv("CAPTCHA_TEST_TOKENS")),
+		Routes:     routes,
+
+		AllowedHostnames: utils.GetEnvList("CAPTCHA_ALLOWED_HOSTNAMES", ""),
+		MaxTokenAge:      utils.GetEnvDurationOrDefault("CAPTCHA_MAX_TOKEN_AGE", 5*time.Minute),
+		ReplayTTL:        utils.GetEnvDurationOrDefault("CAPTCHA_REPLAY_TTL", 10*time.Minute),
+	}
+}
+
+// Route returns the settings for a named route, or an empty config if unknown.
+func (c *CaptchaConfig) Route(name string) CaptchaRouteConfig {
+	return c.Routes[name]
+}
+
+// parseTestTokens parses "token:score,token2:score" into a map.
+// A token without a score is accepted with a score of 1.0.
+func parseTestTokens(raw string) map[string]float64 {
+	tokens := map[string]float64{}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		token, rawScore, found := strings.Cut(entry, ":")
+		score := 1.0
+		if found {
+			if v, err := strconv.ParseFloat(rawScore, 64); err == nil {
+				score = v
+			}
+		}
+		tokens[strings.TrimSpace(token)] = score
+	}
+	return tokens
+}
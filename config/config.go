@@ -0,0 +1,194 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Config is every setting the application needs, loaded once at boot.
+type Config struct {
+	App             *AppConfig
+	API             *APIConfig
+	RateLimit       *RateLimitingConfig
+	Mail            *MailConfig
+	EmailValidation *EmailValidationConfig
+	CORS            *CORSConfig
+	SecurityHeaders *SecurityHeadersConfig
+	Captcha         *CaptchaConfig
+	StepUp          *StepUpConfig
+	Spam            *SpamConfig
+	Discord         *DiscordConfig
+	Notification    *NotificationConfig
+	Webhooks        *WebhookConfig
+	Logging         *LoggingConfig
+	Metrics         *MetricsConfig
+	Tracing         *TracingConfig
+	Health          *HealthConfig
+}
+
+// Load reads the optional YAML config file into the environment, builds the
+// typed configuration and validates it. The error lists every problem found.
+func Load() (*Config, error) {
+	if err := loadYAML(); err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{
+		App:             LoadAppConfig(),
+		API:             LoadAPIConfig(),
+		RateLimit:       LoadRateLimitingConfig(),
+		Mail:            LoadMailConfig(),
+		EmailValidation: LoadEmailValidationConfig(),
+		CORS:            LoadCORSConfig(),
+		SecurityHeaders: LoadSecurityHeadersConfig(),
+		Captcha:         LoadCaptchaConfig(),
+		StepUp:          LoadStepUpConfig(),
+		Spam:            LoadSpamConfig(),
+		Discord:         LoadDiscordConfig(),
+		Notification:    LoadNotificationConfig(),
+		Webhooks:        LoadWebhookConfig(),
+		Logging:         LoadLoggingConfig(),
+		Metrics:         LoadMetricsConfig(),
+		Tracing:         LoadTracingConfig(),
+		Health:          LoadHealthConfig(),
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// captchaProviders are the providers captcha.New accepts.
+var captchaProviders = []string{"recaptcha_v3", "recaptcha_v2", "hcaptcha", "turnstile"}
+
+// Validate checks that required settings are present and that every set
+// value has the right format.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	for _, name := range setKeys() {
+		if k, ok := LookupKey(name); ok && os.Getenv(name) != "" {
+			if err := k.Check(os.Getenv(name)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	required := map[string]string{
+		"SMTP_HOST":                    c.Mail.Host,
+		"EMAIL_FROM":                   c.Mail.From,
+		"THIS_PORTFOLIO_CONTACT_EMAIL": c.Mail.ContactEmail,
+		"REDIS_URL":                    os.Getenv("REDIS_URL"),
+		"SMTP_PORT":                    os.Getenv("SMTP_PORT"),
+		"SPAM_MODEL_PATH":              c.Spam.ModelPath,
+	}
+	for _, name := range sortedNames(required) {
+		if required[name] == "" {
+			fail("%s is required", name)
+		}
+	}
+	if c.Mail.Port < 0 || c.Mail.Port > 65535 {
+		fail("SMTP_PORT must be a port number")
+	}
+	if url := os.Getenv("REDIS_URL"); url != "" {
+		if _, err := redis.ParseURL(url); err != nil {
+			fail("REDIS_URL is invalid: %v", err)
+		}
+	}
+	if c.EmailValidation.PrimaryURL == "" && c.EmailValidation.SecondaryURL == "" {
+		fail("PRIMARY_EMAIL_VERIFY_URL or SECONDARY_EMAIL_VERIFY_URL is required")
+	}
+
+	if _, err := c.CORS.OriginMatchers(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.CORS.MaxAge < 0 {
+		fail("CORS_MAX_AGE must not be negative")
+	}
+
+	if c.RateLimit.RateLimit <= 0 || c.RateLimit.BurstLimit <= 0 {
+		fail("RATE_LIMIT_RPS and RATE_LIMIT_BURST must be positive")
+	}
+
+	if f := c.SecurityHeaders.FrameOptions; f != "" && f != "DENY" && f != "SAMEORIGIN" {
+		fail("SECURITY_FRAME_OPTIONS must be DENY, SAMEORIGIN or empty")
+	}
+
+	if !slices.Contains(captchaProviders, c.Captcha.Provider) {
+		fail("CAPTCHA_PROVIDER must be one of %s", strings.Join(captchaProviders, ", "))
+	}
+	if c.Captcha.SecretKey == "" && !c.Captcha.TestMode {
+		fail("CAPTCHA_SECRET_KEY is required unless CAPTCHA_TEST_MODE=true")
+	}
+	if c.StepUp.MinScore > 0 && c.StepUp.CaptchaSecretKey != "" && !slices.Contains(captchaProviders, c.StepUp.CaptchaProvider) {
+		fail("STEPUP_CAPTCHA_PROVIDER must be one of %s", strings.Join(captchaProviders, ", "))
+	}
+
+	if c.Spam.RejectThreshold <= c.Spam.FlagThreshold {
+		fail("SPAM_REJECT_THRESHOLD must be greater than SPAM_FLAG_THRESHOLD")
+	}
+
+	for _, t := range c.Discord.Targets {
+		if t.Name != "default" && t.WebhookURL == "" {
+			fail("DISCORD_TARGET_%s_WEBHOOK_URL is required for target %q", strings.ToUpper(t.Name), t.Name)
+		}
+		if t.RateLimit <= 0 || t.Burst <= 0 {
+			fail("DISCORD_TARGET_%s_RATE_LIMIT and _BURST must be positive", strings.ToUpper(t.Name))
+		}
+	}
+
+	if f := strings.ToLower(c.Logging.Format); f != "json" && f != "text" {
+		fail("LOG_FORMAT must be json or text")
+	}
+	if e := strings.ToLower(c.Tracing.Exporter); e != "none" && e != "stdout" && e != "otlp" {
+		fail("OTEL_TRACES_EXPORTER must be none, stdout or otlp")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		fail("OTEL_TRACES_SAMPLER_ARG must be between 0 and 1")
+	}
+
+	return errors.Join(errs...)
+}
+
+// Summary returns the known settings that are set, as alternating key/value
+// pairs for slog, with secrets redacted.
+func (c *Config) Summary() []interface{} {
+	var out []interface{}
+	for _, name := range setKeys() {
+		k, ok := LookupKey(name)
+		if !ok || os.Getenv(name) == "" {
+			continue
+		}
+		out = append(out, name, redact(k, os.Getenv(name)))
+	}
+	return out
+}
+
+// setKeys returns the names of all environment variables, sorted.
+func setKeys() []string {
+	var names []string
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedNames(m map[string]string) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -1,39 +1,101 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"portfolio-backend/utils"
 )
 
 type CORSConfig struct {
-	Paths                  []string
+	Paths                  []string // request paths CORS applies to, e.g. "*" or "api/*"
 	AllowedMethods         []string
-	AllowedOrigins         []string
-	AllowedOriginsPatterns []string
+	AllowedOrigins         []string // exact origins; "*" allows any
+	AllowedOriginsPatterns []string // wildcard origins such as https://*.example.com, or ^regex$
 	AllowedHeaders         []string
 	ExposedHeaders         []string
 	MaxAge                 int
 	SupportsCredentials    bool
+	AllowPrivateNetwork    bool // answer Private Network Access preflights from public sites
 }
 
+// LoadCORSConfig reads the CORS policy from env (or the matching cors.* keys
+// of the config file).
 func LoadCORSConfig() *CORSConfig {
-	rawOrigins := utils.GetEnvOrDefault("ALLOWED_ORIGINS", "http://localhost:5173,http://localhost:3000")
-	origins := []string{}
-	for _, o := range strings.Split(rawOrigins, ",") {
-		o = strings.TrimSpace(o)
-		if o != "" {
-			origins = append(origins, o)
-		}
+	maxAge, err := strconv.Atoi(os.Getenv("CORS_MAX_AGE"))
+	if err != nil {
+		maxAge = 3600
 	}
 	return &CORSConfig{
-		Paths:                  []string{"api/*", "sanctum/csrf-cookie"},
-		AllowedMethods:         []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedOrigins:         origins,
-		AllowedOriginsPatterns: []string{},
-		AllowedHeaders:         []string{"*"},
-		ExposedHeaders:         []string{},
-		MaxAge:                 3600,
-		SupportsCredentials:    false,
+		Paths:                  utils.GetEnvList("CORS_PATHS", "*"),
+		AllowedMethods:         utils.GetEnvList("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS"),
+		AllowedOrigins:         utils.GetEnvList("ALLOWED_ORIGINS", "http://localhost:5173,http://localhost:3000"),
+		AllowedOriginsPatterns: utils.GetEnvList("CORS_ALLOWED_ORIGINS_PATTERNS", ""),
+		AllowedHeaders:         utils.GetEnvList("CORS_ALLOWED_HEADERS", "*"),
+		ExposedHeaders:         utils.GetEnvList("CORS_EXPOSED_HEADERS", ""),
+		MaxAge:                 maxAge,
+		SupportsCredentials:    os.Getenv("CORS_SUPPORTS_CREDENTIALS") == "true",
+		AllowPrivateNetwork:    os.Getenv("CORS_ALLOW_PRIVATE_NETWORK") == "true",
+	}
+}
+
+// OriginMatchers compiles AllowedOriginsPatterns. A pattern starting with ^
+// is a regular expression matched against the whole origin. Otherwise it is
+// scheme://host[:port], where a leading "*." in the host matches one or more
+// subdomain labels, "*" as the port matches any port and a missing scheme
+// means https. Patterns are anchored, so https://*.example.com never matches
+// https://evil-example.com or https://a.example.com.attacker.io.
+func (c *CORSConfig) OriginMatchers() ([]*regexp.Regexp, error) {
+	var matchers []*regexp.Regexp
+	for _, p := range c.AllowedOriginsPatterns {
+		re, err := compileOriginPattern(p)
+		if err != nil {
+			return nil, fmt.Errorf("CORS_ALLOWED_ORIGINS_PATTERNS: invalid pattern %q: %v", p, err)
+		}
+		matchers = append(matchers, re)
+	}
+	return matchers, nil
+}
+
+func compileOriginPattern(p string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(p, "^") {
+		return regexp.Compile("^(?:" + strings.TrimSuffix(p[1:], "$") + ")$")
+	}
+
+	scheme, hostport, found := strings.Cut(strings.ToLower(p), "://")
+	if !found {
+		scheme, hostport = "https", scheme
+	}
+	host, port, hasPort := strings.Cut(hostport, ":")
+	if scheme == "" || host == "" || strings.ContainsAny(hostport, "/?#") {
+		return nil, fmt.Errorf("expected scheme://host[:port]")
+	}
+
+	var b strings.Builder
+	b.WriteString("^" + regexp.QuoteMeta(scheme) + "://")
+	for i, label := range strings.Split(host, ".") {
+		switch {
+		case label == "*" && i == 0:
+			b.WriteString(`(?:[a-z0-9-]+\.)*[a-z0-9-]+`)
+		case label == "*":
+			return nil, fmt.Errorf("* is only allowed as the first host label")
+		default:
+			if i > 0 {
+				b.WriteString(`\.`)
+			}
+			b.WriteString(regexp.QuoteMeta(label))
+		}
+	}
+	if hasPort {
+		if port == "*" {
+			b.WriteString(`:[0-9]+`)
+		} else {
+			b.WriteString(":" + regexp.QuoteMeta(port))
+		}
 	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
 }
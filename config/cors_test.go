@@ -0,0 +1,71 @@
+package config
+
+import "testing"
+
+func TestOriginMatchers(t *testing.T) {
+	tests := []struct {
+		pattern string
+		origin  string
+		want    bool
+	}{
+		// Subdomain wildcards
+		{"https://*.example.com", "https://a.example.com", true},
+		{"https://*.example.com", "https://a.b.example.com", true},
+		{"https://*.example.com", "https://example.com", false},
+		{"https://*.example.com", "https://evil-example.com", false},
+		{"https://*.example.com", "https://a.example.com.evil.io", false},
+		{"https://*.example.com", "https://aexample.com", false},
+		{"*.example.com", "https://a.example.com", true},
+
+		// Scheme must match
+		{"https://*.example.com", "http://a.example.com", false},
+		{"http://localhost", "https://localhost", false},
+		{"example.com", "http://example.com", false},
+
+		// Port must match; * allows any
+		{"https://*.example.com", "https://a.example.com:8443", false},
+		{"http://localhost:3000", "http://localhost:3000", true},
+		{"http://localhost:3000", "http://localhost:3001", false},
+		{"http://localhost:3000", "http://localhost", false},
+		{"http://localhost:*", "http://localhost:5173", true},
+		{"http://localhost:*", "http://localhost", false},
+		{"http://localhost:*", "http://localhost:abc", false},
+
+		// Dots are literal
+		{"https://api.example.com", "https://apixexample.com", false},
+
+		// Regular expressions are anchored
+		{"^https://[a-z]+\\.example\\.com$", "https://app.example.com", true},
+		{"^https://[a-z]+\\.example\\.com", "https://app.example.com.evil.io", false},
+		{"^https://(a|b)\\.example\\.com$", "https://b.example.com", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.origin, func(t *testing.T) {
+			c := &CORSConfig{AllowedOriginsPatterns: []string{tt.pattern}}
+			matchers, err := c.OriginMatchers()
+			if err != nil {
+				t.Fatalf("OriginMatchers error: %v", err)
+			}
+			if got := matchers[0].MatchString(tt.origin); got != tt.want {
+				t.Errorf("%q matches %q = %v, want %v", tt.pattern, tt.origin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOriginMatchersInvalid(t *testing.T) {
+	for _, pattern := range []string{
+		"https://",
+		"https://example.com/path",
+		"https://a.*.example.com",
+		"https://example.com?x=1",
+		"^https://(unclosed",
+	} {
+		t.Run(pattern, func(t *testing.T) {
+			c := &CORSConfig{AllowedOriginsPatterns: []string{pattern}}
+			if _, err := c.OriginMatchers(); err == nil {
+				t.Errorf("OriginMatchers(%q) error = nil, want an error", pattern)
+			}
+		})
+	}
+}
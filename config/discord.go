@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"strings"
+
+	"portfolio-backend/utils"
+)
+
+// DiscordTargetConfig holds one named webhook target of the Discord proxy.
+type DiscordTargetConfig struct {
+	Name         string
+	WebhookURL   string
+	APIKeys      []string // accepted X-API-KEY values; empty leaves the target open
+	RateLimit    int      // requests per hour and client
+	Burst        int      // of those, how many may arrive at once
+	TemplatePath string   // optional text/template turning the request JSON into a Discord payload
+}
+
+type DiscordConfig struct {
+	QueueSize     int   // pending background deliveries before the proxy answers 503
+	QueueMaxBytes int64 // total body bytes held by the queue, including retries; 503 beyond it
+	QueueJobBytes int64 // body bytes of one queued message, 413 beyond it
+	MaxAttempts   int   // delivery attempts per queued message
+
+	MaxBodyBytes     int64    // request body cap, 413 beyond it
+	MaxFileBytes     int64    // per uploaded file
+	MaxFiles         int      // files per message
+	AllowedFileTypes []string // sniffed MIME types; "image/*" allows a family
+
+	// Targets always contains "default", served at /api/v1/discord-webhook and
+	// configured by the legacy DISCORD_WEBHOOK_URL and DISCORD_PROXY_KEY.
+	Targets []DiscordTargetConfig
+}
+
+// LoadDiscordConfig reads the proxy settings from env. Named targets are
+// listed in DISCORD_TARGETS and use DISCORD_TARGET_<NAME>_WEBHOOK_URL,
+// _API_KEYS, _RATE_LIMIT, _BURST and _TEMPLATE.
+func LoadDiscordConfig() *DiscordConfig {
+	targets := []DiscordTargetConfig{{
+		Name:       "default",
+		WebhookURL: os.Getenv("DISCORD_WEBHOOK_URL"),
+		APIKeys:    utils.GetEnvList("DISCORD_PROXY_KEY", ""),
+		RateLimit:  1,
+		Burst:      1,
+	}}
+	for _, name := range utils.GetEnvList("DISCORD_TARGETS", "") {
+		name = strings.ToLower(name)
+		if name == "default" {
+			continue
+		}
+		prefix := "DISCORD_TARGET_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		targets = append(targets, DiscordTargetConfig{
+			Name:         name,
+			WebhookURL:   os.Getenv(prefix + "WEBHOOK_URL"),
+			APIKeys:      utils.GetEnvList(prefix+"API_KEYS", ""),
+			RateLimit:    int(envFloat(prefix+"RATE_LIMIT", 1)),
+			Burst:        int(envFloat(prefix+"BURST", 1)),
+			TemplatePath: os.Getenv(prefix + "TEMPLATE"),
+		})
+	}
+
+	return &DiscordConfig{
+		QueueSize:     int(envFloat("DISCORD_QUEUE_SIZE", 100)),
+		QueueMaxBytes: int64(envFloat("DISCORD_QUEUE_MAX_BYTES", 64<<20)),
+		QueueJobBytes: int64(envFloat("DISCORD_QUEUE_MAX_JOB_BYTES", 4<<20)),
+		MaxAttempts:   int(envFloat("DISCORD_MAX_ATTEMPTS", 5)),
+		Targets:       targets,
+
+		MaxBodyBytes:     int64(envFloat("DISCORD_MAX_BODY_BYTES", 10<<20)),
+		MaxFileBytes:     int64(envFloat("DISCORD_MAX_FILE_BYTES", 8<<20)),
+		MaxFiles:         int(envFloat("DISCORD_MAX_FILES", 10)),
+		AllowedFileTypes: utils.GetEnvList("DISCORD_ALLOWED_FILE_TYPES", "image/png,image/jpeg,image/gif,image/webp,text/plain,application/pdf"),
+	}
+}
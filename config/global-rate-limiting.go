@@ -1,13 +1,30 @@
 package config
 
+import (
+	"os"
+	"strconv"
+)
+
+// RateLimitingConfig is the global request rate shared by every route
+// except the health checks. Per-route limits are set in routes/web.go.
 type RateLimitingConfig struct {
-	RateLimit  int // requests per second
-	BurstLimit int // max burst size
+	RateLimit  int  // requests per second
+	BurstLimit int  // max burst size
+	PerRoute   bool // apply the per-route, per-client limits; off when APP_ENV is local
 }
 
-func GlobalLoadRateLimitingConfig() RateLimitingConfig {
-	return RateLimitingConfig{
-		RateLimit:  5,
-		BurstLimit: 10,
+func LoadRateLimitingConfig() *RateLimitingConfig {
+	rps, err := strconv.Atoi(os.Getenv("RATE_LIMIT_RPS"))
+	if err != nil {
+		rps = 5
+	}
+	burst, err := strconv.Atoi(os.Getenv("RATE_LIMIT_BURST"))
+	if err != nil {
+		burst = 10
+	}
+	return &RateLimitingConfig{
+		RateLimit:  rps,
+		BurstLimit: burst,
+		PerRoute:   os.Getenv("APP_ENV") != "local",
 	}
 }
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"time"
+
+	"portfolio-backend/utils"
+)
+
+// HealthConfig tunes the readiness probes behind /readyz and /status.
+type HealthConfig struct {
+	ProbeTimeout time.Duration
+	CacheTTL     time.Duration // probe results are reused this long
+	CheckSMTP    bool          // disable when the SMTP server throttles connections
+}
+
+func LoadHealthConfig() *HealthConfig {
+	return &HealthConfig{
+		ProbeTimeout: utils.GetEnvDurationOrDefault("HEALTH_PROBE_TIMEOUT", 3*time.Second),
+		CacheTTL:     utils.GetEnvDurationOrDefault("HEALTH_CACHE_TTL", 10*time.Second),
+		CheckSMTP:    utils.GetEnvOrDefault("HEALTH_CHECK_SMTP", "true") == "true",
+	}
+}
@@ -0,0 +1,245 @@
+package config
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"portfolio-backend/utils"
+)
+
+// Kind is the format a setting's raw value must have.
+type Kind int
+
+const (
+	KindString Kind = iota
+	KindInt
+	KindFloat
+	KindBool
+	KindDuration
+	KindURL
+	KindEmail
+	KindDate // YYYY-MM-DD
+)
+
+// Key describes one environment setting.
+type Key struct {
+	Name       string
+	Kind       Kind
+	Secret     bool   // redacted in the startup summary
+	Deprecated string // replacement setting; using this one logs a warning
+}
+
+// Keys lists every setting the application reads. Values are format-checked
+// at boot; secrets are never printed.
+var Keys = []Key{
+	{Name: "APP_NAME"},
+	{Name: "APP_ENV"},
+	{Name: "APP_URL", Kind: KindURL},
+	{Name: "PORT", Kind: KindInt},
+	{Name: "SERVER_PORT", Kind: KindInt, Deprecated: "PORT"},
+	{Name: "SERVER_URL", Kind: KindURL},
+	{Name: "CONFIG_FILE"},
+	{Name: "ADMIN_API_KEY", Secret: true},
+	{Name: "DEBUG_DISCORD_PROXY", Kind: KindBool},
+	{Name: "API_LEGACY_ROUTES", Kind: KindBool},
+	{Name: "API_LEGACY_DEPRECATED_AT", Kind: KindDate},
+	{Name: "API_LEGACY_SUNSET", Kind: KindDate},
+	{Name: "RATE_LIMIT_RPS", Kind: KindInt},
+	{Name: "RATE_LIMIT_BURST", Kind: KindInt},
+
+	{Name: "LOG_LEVEL"},
+	{Name: "LOG_FORMAT"},
+	{Name: "METRICS_ENABLED", Kind: KindBool},
+	{Name: "METRICS_API_KEY", Secret: true},
+	{Name: "OTEL_TRACES_EXPORTER"},
+	{Name: "OTEL_SERVICE_NAME"},
+	{Name: "OTEL_TRACES_SAMPLER_ARG", Kind: KindFloat},
+	{Name: "OTEL_EXPORTER_OTLP_HEADERS", Secret: true},
+	{Name: "OTEL_EXPORTER_OTLP_TRACES_HEADERS", Secret: true},
+	{Name: "HEALTH_PROBE_TIMEOUT", Kind: KindDuration},
+	{Name: "HEALTH_CACHE_TTL", Kind: KindDuration},
+	{Name: "HEALTH_CHECK_SMTP", Kind: KindBool},
+
+	{Name: "ALLOWED_ORIGINS"},
+	{Name: "CORS_PATHS"},
+	{Name: "CORS_ALLOWED_METHODS"},
+	{Name: "CORS_ALLOWED_ORIGINS_PATTERNS"},
+	{Name: "CORS_ALLOWED_HEADERS"},
+	{Name: "CORS_EXPOSED_HEADERS"},
+	{Name: "CORS_MAX_AGE", Kind: KindInt},
+	{Name: "CORS_SUPPORTS_CREDENTIALS", Kind: KindBool},
+	{Name: "CORS_ALLOW_PRIVATE_NETWORK", Kind: KindBool},
+	{Name: "SECURITY_HEADERS_ENABLED", Kind: KindBool},
+	{Name: "SECURITY_HSTS_MAX_AGE", Kind: KindInt},
+	{Name: "SECURITY_HSTS_INCLUDE_SUBDOMAINS", Kind: KindBool},
+	{Name: "SECURITY_HSTS_PRELOAD", Kind: KindBool},
+	{Name: "SECURITY_REFERRER_POLICY"},
+	{Name: "SECURITY_PERMISSIONS_POLICY"},
+	{Name: "SECURITY_FRAME_OPTIONS"},
+	{Name: "SECURITY_CSP"},
+	{Name: "SECURITY_HTML_CSP"},
+	{Name: "SECURITY_HTML_PATHS"},
+	{Name: "REDIS_URL", Kind: KindURL, Secret: true},
+
+	{Name: "THIS_PORTFOLIO_CONTACT_EMAIL", Kind: KindEmail},
+	{Name: "EMAIL_FROM"},
+	{Name: "SMTP_HOST"},
+	{Name: "SMTP_PORT", Kind: KindInt},
+	{Name: "SMTP_USER"},
+	{Name: "SMTP_PASS", Secret: true},
+	{Name: "PRIMARY_EMAIL_VERIFY_URL", Kind: KindURL},
+	{Name: "PRIMARY_EMAIL_VERIFY_KEY", Secret: true},
+	{Name: "SECONDARY_EMAIL_VERIFY_URL", Kind: KindURL},
+	{Name: "SECONDARY_EMAIL_VERIFY_KEY", Secret: true},
+
+	{Name: "CAPTCHA_PROVIDER"},
+	{Name: "CAPTCHA_SECRET_KEY", Secret: true},
+	{Name: "RECAPTCHA_SECRET_KEY", Secret: true, Deprecated: "CAPTCHA_SECRET_KEY"},
+	{Name: "CAPTCHA_TEST_MODE", Kind: KindBool},
+	{Name: "CAPTCHA_TEST_TOKENS", Secret: true},
+	{Name: "CAPTCHA_ALLOWED_HOSTNAMES"},
+	{Name: "CAPTCHA_MAX_TOKEN_AGE", Kind: KindDuration},
+	{Name: "CAPTCHA_REPLAY_TTL", Kind: KindDuration},
+
+	{Name: "STEPUP_MIN_SCORE", Kind: KindFloat},
+	{Name: "STEPUP_TTL", Kind: KindDuration},
+	{Name: "STEPUP_CAPTCHA_PROVIDER"},
+	{Name: "STEPUP_CAPTCHA_SECRET_KEY", Secret: true},
+	{Name: "STEPUP_EMAIL_CONFIRMATION", Kind: KindBool},
+	{Name: "STEPUP_CONFIRM_URL", Kind: KindURL},
+	{Name: "STEPUP_CONFIRM_EMAILS_PER_HOUR", Kind: KindInt},
+	{Name: "STEPUP_REDIRECT_URL", Kind: KindURL},
+
+	{Name: "SPAM_FLAG_THRESHOLD", Kind: KindFloat},
+	{Name: "SPAM_REJECT_THRESHOLD", Kind: KindFloat},
+	{Name: "SPAM_HONEYPOT_SCORE", Kind: KindFloat},
+	{Name: "SPAM_FORM_TOKEN_SECRET", Secret: true},
+	{Name: "SPAM_MIN_FILL_TIME", Kind: KindDuration},
+	{Name: "SPAM_MAX_FORM_AGE", Kind: KindDuration},
+	{Name: "SPAM_TOO_FAST_SCORE", Kind: KindFloat},
+	{Name: "SPAM_INVALID_TOKEN_SCORE", Kind: KindFloat},
+	{Name: "SPAM_MAX_LINKS", Kind: KindInt},
+	{Name: "SPAM_LINK_SCORE", Kind: KindFloat},
+	{Name: "SPAM_KEYWORDS"},
+	{Name: "SPAM_KEYWORD_SCORE", Kind: KindFloat},
+	{Name: "SPAM_ALLOWED_SCRIPTS"},
+	{Name: "SPAM_MAX_FOREIGN_RATIO", Kind: KindFloat},
+	{Name: "SPAM_FOREIGN_SCORE", Kind: KindFloat},
+	{Name: "SPAM_INVISIBLE_SCORE", Kind: KindFloat},
+	{Name: "SPAM_MODEL_PATH"},
+	{Name: "SPAM_MODEL_MIN_DOCS", Kind: KindInt},
+	{Name: "SPAM_CLASSIFIER_THRESHOLD", Kind: KindFloat},
+	{Name: "SPAM_CLASSIFIER_SCORE", Kind: KindFloat},
+	{Name: "SPAM_SUBMISSION_TTL", Kind: KindDuration},
+
+	{Name: "DISCORD_WEBHOOK_URL", Kind: KindURL, Secret: true},
+	{Name: "DISCORD_PROXY_KEY", Secret: true},
+	{Name: "DISCORD_TARGETS"},
+	{Name: "DISCORD_QUEUE_SIZE", Kind: KindInt},
+	{Name: "DISCORD_QUEUE_MAX_BYTES", Kind: KindInt},
+	{Name: "DISCORD_QUEUE_MAX_JOB_BYTES", Kind: KindInt},
+	{Name: "DISCORD_MAX_ATTEMPTS", Kind: KindInt},
+	{Name: "DISCORD_MAX_BODY_BYTES", Kind: KindInt},
+	{Name: "DISCORD_MAX_FILE_BYTES", Kind: KindInt},
+	{Name: "DISCORD_MAX_FILES", Kind: KindInt},
+	{Name: "DISCORD_ALLOWED_FILE_TYPES"},
+
+	{Name: "DISCORD_NOTIFY_CONTACT", Kind: KindBool},
+	{Name: "DISCORD_CONTACT_WEBHOOK_URL", Kind: KindURL, Secret: true},
+	{Name: "NOTIFY_SLACK_WEBHOOK_URL", Kind: KindURL, Secret: true},
+	{Name: "NOTIFY_TELEGRAM_BOT_TOKEN", Secret: true},
+	{Name: "NOTIFY_TELEGRAM_CHAT_ID"},
+	{Name: "NOTIFY_WEBHOOK_URL", Kind: KindURL},
+	{Name: "NOTIFY_WEBHOOK_SECRET", Secret: true},
+	{Name: "GEOIP_COUNTRY_HEADER"},
+	{Name: "GEOIP_LOOKUP_URL"},
+
+	{Name: "WEBHOOK_SUBSCRIPTIONS", Secret: true},
+	{Name: "WEBHOOK_SUBSCRIPTIONS_FILE"},
+	{Name: "WEBHOOK_MAX_ATTEMPTS", Kind: KindInt},
+	{Name: "WEBHOOK_BASE_BACKOFF", Kind: KindDuration},
+	{Name: "WEBHOOK_LOG_TTL", Kind: KindDuration},
+}
+
+// keyPatterns cover the settings whose names embed a route, target or event,
+// plus the standard OpenTelemetry variables.
+var keyPatterns = []struct {
+	Pattern *regexp.Regexp
+	Key     Key
+}{
+	{regexp.MustCompile(`^CAPTCHA_[A-Z0-9_]+_ACTION$`), Key{}},
+	{regexp.MustCompile(`^CAPTCHA_[A-Z0-9_]+_MIN_SCORE$`), Key{Kind: KindFloat}},
+	{regexp.MustCompile(`^DISCORD_TARGET_[A-Z0-9_]+_WEBHOOK_URL$`), Key{Kind: KindURL, Secret: true}},
+	{regexp.MustCompile(`^DISCORD_TARGET_[A-Z0-9_]+_API_KEYS$`), Key{Secret: true}},
+	{regexp.MustCompile(`^DISCORD_TARGET_[A-Z0-9_]+_(RATE_LIMIT|BURST)$`), Key{Kind: KindInt}},
+	{regexp.MustCompile(`^DISCORD_TARGET_[A-Z0-9_]+_TEMPLATE$`), Key{}},
+	{regexp.MustCompile(`^NOTIFY_ROUTE_[A-Z0-9_]+$`), Key{}},
+	{regexp.MustCompile(`^OTEL_[A-Z0-9_]+$`), Key{}}, // read by the OpenTelemetry SDK
+}
+
+// LookupKey returns the description of a setting, matching dynamic names
+// such as DISCORD_TARGET_ALERTS_WEBHOOK_URL against their pattern.
+func LookupKey(name string) (Key, bool) {
+	for _, k := range Keys {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	for _, p := range keyPatterns {
+		if p.Pattern.MatchString(name) {
+			k := p.Key
+			k.Name = name
+			return k, true
+		}
+	}
+	return Key{}, false
+}
+
+// Check reports why value is not a valid k, or nil.
+func (k Key) Check(value string) error {
+	var err error
+	switch k.Kind {
+	case KindInt:
+		_, err = strconv.Atoi(value)
+	case KindFloat:
+		_, err = strconv.ParseFloat(value, 64)
+	case KindBool:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("%s must be true or false", k.Name)
+		}
+	case KindDuration:
+		// Same rules as utils.GetEnvDurationOrDefault, so plain seconds pass
+		_, err = utils.ParseDuration(value)
+	case KindURL:
+		u, perr := url.Parse(value)
+		if perr != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("%s must be an absolute URL", k.Name)
+		}
+	case KindEmail:
+		_, err = mail.ParseAddress(value)
+	case KindDate:
+		_, err = time.Parse(time.DateOnly, value)
+	}
+	if err != nil {
+		return fmt.Errorf("%s has an invalid value %q", k.Name, redact(k, value))
+	}
+	return nil
+}
+
+// redact hides secret values, keeping only whether they are set.
+func redact(k Key, value string) string {
+	if k.Secret && value != "" {
+		return "[redacted]"
+	}
+	return value
+}
+
+// envKeyName turns a nested YAML path such as smtp.host into SMTP_HOST.
+func envKeyName(parts ...string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(strings.Join(parts, "_")))
+}
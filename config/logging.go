@@ -0,0 +1,27 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+
+	"portfolio-backend/services/logging"
+	"portfolio-backend/utils"
+)
+
+type LoggingConfig struct {
+	Level  slog.Level
+	Format string // json or text
+}
+
+// LoadLoggingConfig reads LOG_LEVEL (debug, info, warn, error) and LOG_FORMAT.
+// Logs are JSON unless APP_ENV is local, where text is easier to read.
+func LoadLoggingConfig() *LoggingConfig {
+	format := "json"
+	if os.Getenv("APP_ENV") == "local" {
+		format = "text"
+	}
+	return &LoggingConfig{
+		Level:  logging.ParseLevel(utils.GetEnvOrDefault("LOG_LEVEL", "info")),
+		Format: utils.GetEnvOrDefault("LOG_FORMAT", format),
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+// MailConfig holds the SMTP server and the addresses contact emails use.
+type MailConfig struct {
+	Host         string
+	Port         int
+	User         string
+	Password     string
+	From         string
+	ContactEmail string // where contact form messages are delivered
+}
+
+func LoadMailConfig() *MailConfig {
+	port, _ := strconv.Atoi(os.Getenv("SMTP_PORT"))
+	return &MailConfig{
+		Host:         os.Getenv("SMTP_HOST"),
+		Port:         port,
+		User:         os.Getenv("SMTP_USER"),
+		Password:     os.Getenv("SMTP_PASS"),
+		From:         os.Getenv("EMAIL_FROM"),
+		ContactEmail: os.Getenv("THIS_PORTFOLIO_CONTACT_EMAIL"),
+	}
+}
+
+// EmailValidationConfig holds the sender address verification APIs. The
+// secondary API is only called when the primary one fails.
+type EmailValidationConfig struct {
+	PrimaryURL   string
+	PrimaryKey   string
+	SecondaryURL string
+	SecondaryKey string
+}
+
+func LoadEmailValidationConfig() *EmailValidationConfig {
+	return &EmailValidationConfig{
+		PrimaryURL:   os.Getenv("PRIMARY_EMAIL_VERIFY_URL"),
+		PrimaryKey:   os.Getenv("PRIMARY_EMAIL_VERIFY_KEY"),
+		SecondaryURL: os.Getenv("SECONDARY_EMAIL_VERIFY_URL"),
+		SecondaryKey: os.Getenv("SECONDARY_EMAIL_VERIFY_KEY"),
+	}
+}
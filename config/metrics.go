@@ -0,0 +1,21 @@
+package config
+
+import (
+	"os"
+
+	"portfolio-backend/utils"
+)
+
+// MetricsConfig controls the Prometheus endpoint. An empty APIKey leaves
+// /metrics open, which is fine when only the scraper can reach it.
+type MetricsConfig struct {
+	Enabled bool
+	APIKey  string
+}
+
+func LoadMetricsConfig() *MetricsConfig {
+	return &MetricsConfig{
+		Enabled: utils.GetEnvOrDefault("METRICS_ENABLED", "true") == "true",
+		APIKey:  os.Getenv("METRICS_API_KEY"),
+	}
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"strings"
+
+	"portfolio-backend/utils"
+)
+
+// NotificationConfig holds the notification channels and which events each receives.
+// A channel is enabled when its required settings are present.
+type NotificationConfig struct {
+	DiscordWebhookURL string
+
+	SlackWebhookURL string
+
+	TelegramBotToken string
+	TelegramChatID   string
+
+	WebhookURL    string
+	WebhookSecret string
+
+	// Routes maps an event type to channel names.
+	Routes map[string][]string
+
+	GeoIPCountryHeader string // set by the proxy in front of the app; empty ignores country headers
+	GeoIPLookupURL     string
+}
+
+// Event types that can be routed to channels.
+const (
+	EventContactReceived       = "contact.received"
+	EventContactDeliveryFailed = "contact.delivery_failed"
+	EventContactSpamBlocked    = "contact.spam_blocked"
+)
+
+// EventTypes lists every event type, in the order used for default routing.
+var EventTypes = []string{EventContactReceived, EventContactDeliveryFailed, EventContactSpamBlocked}
+
+// defaultRoutedEvents go to every enabled channel unless overridden; other
+// events (e.g. contact.spam_blocked) are only sent when routed explicitly.
+var defaultRoutedEvents = []string{EventContactReceived, EventContactDeliveryFailed}
+
+// LoadNotificationConfig reads channels from env. Routes use
+// NOTIFY_ROUTE_<EVENT>, e.g. NOTIFY_ROUTE_CONTACT_SPAM_BLOCKED=discord,telegram.
+func LoadNotificationConfig() *NotificationConfig {
+	cfg := &NotificationConfig{
+		SlackWebhookURL:    os.Getenv("NOTIFY_SLACK_WEBHOOK_URL"),
+		TelegramBotToken:   os.Getenv("NOTIFY_TELEGRAM_BOT_TOKEN"),
+		TelegramChatID:     os.Getenv("NOTIFY_TELEGRAM_CHAT_ID"),
+		WebhookURL:         os.Getenv("NOTIFY_WEBHOOK_URL"),
+		WebhookSecret:      os.Getenv("NOTIFY_WEBHOOK_SECRET"),
+		Routes:             map[string][]string{},
+		GeoIPCountryHeader: os.Getenv("GEOIP_COUNTRY_HEADER"),
+		GeoIPLookupURL:     os.Getenv("GEOIP_LOOKUP_URL"),
+	}
+	if os.Getenv("DISCORD_NOTIFY_CONTACT") == "true" {
+		cfg.DiscordWebhookURL = utils.GetEnvOrDefault("DISCORD_CONTACT_WEBHOOK_URL", os.Getenv("DISCORD_WEBHOOK_URL"))
+	}
+
+	enabled := cfg.EnabledChannels()
+	for _, event := range EventTypes {
+		key := "NOTIFY_ROUTE_" + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(event))
+		if _, set := os.LookupEnv(key); set {
+			cfg.Routes[event] = utils.GetEnvList(key, "")
+			continue
+		}
+		for _, e := range defaultRoutedEvents {
+			if e == event {
+				cfg.Routes[event] = enabled
+			}
+		}
+	}
+	return cfg
+}
+
+// EnabledChannels returns the names of the channels with complete settings.
+func (c *NotificationConfig) EnabledChannels() []string {
+	channels := []string{}
+	if c.DiscordWebhookURL != "" {
+		channels = append(channels, "discord")
+	}
+	if c.SlackWebhookURL != "" {
+		channels = append(channels, "slack")
+	}
+	if c.TelegramBotToken != "" && c.TelegramChatID != "" {
+		channels = append(channels, "telegram")
+	}
+	if c.WebhookURL != "" {
+		channels = append(channels, "webhook")
+	}
+	return channels
+}
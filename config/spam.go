@@ -0,0 +1,79 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"portfolio-backend/utils"
+)
+
+// SpamConfig holds the thresholds and heuristics of the contact form spam pipeline.
+type SpamConfig struct {
+	FlagThreshold   float64
+	RejectThreshold float64
+
+	HoneypotScore float64
+
+	FormTokenSecret   string // empty disables the timing signal
+	MinFillTime       time.Duration
+	MaxFormAge        time.Duration
+	TooFastScore      float64
+	InvalidTokenScore float64
+
+	MaxLinks     int
+	LinkScore    float64
+	Keywords     []string
+	KeywordScore float64
+
+	AllowedScripts  []string // unicode script names, e.g. Latin, Greek
+	MaxForeignRatio float64
+	ForeignScore    float64
+	InvisibleScore  float64
+
+	ModelPath           string // naive Bayes model file, retrained from admin labels; must be on persistent storage
+	ModelMinDocs        int    // spam and ham documents needed before the classifier scores
+	ClassifierThreshold float64
+	ClassifierScore     float64
+	SubmissionTTL       time.Duration // how long submissions stay available for labelling
+}
+
+func LoadSpamConfig() *SpamConfig {
+	return &SpamConfig{
+		FlagThreshold:   envFloat("SPAM_FLAG_THRESHOLD", 3),
+		RejectThreshold: envFloat("SPAM_REJECT_THRESHOLD", 6),
+
+		HoneypotScore: envFloat("SPAM_HONEYPOT_SCORE", 10),
+
+		FormTokenSecret:   os.Getenv("SPAM_FORM_TOKEN_SECRET"),
+		MinFillTime:       utils.GetEnvDurationOrDefault("SPAM_MIN_FILL_TIME", 3*time.Second),
+		MaxFormAge:        utils.GetEnvDurationOrDefault("SPAM_MAX_FORM_AGE", 2*time.Hour),
+		TooFastScore:      envFloat("SPAM_TOO_FAST_SCORE", 5),
+		InvalidTokenScore: envFloat("SPAM_INVALID_TOKEN_SCORE", 1),
+
+		MaxLinks:  int(envFloat("SPAM_MAX_LINKS", 2)),
+		LinkScore: envFloat("SPAM_LINK_SCORE", 1),
+		Keywords: utils.GetEnvList("SPAM_KEYWORDS",
+			"seo,backlink,guest post,rank your website,first page of google,casino,crypto,viagra,loan offer,web traffic"),
+		KeywordScore: envFloat("SPAM_KEYWORD_SCORE", 1),
+
+		AllowedScripts:  utils.GetEnvList("SPAM_ALLOWED_SCRIPTS", "Latin"),
+		MaxForeignRatio: envFloat("SPAM_MAX_FOREIGN_RATIO", 0.3),
+		ForeignScore:    envFloat("SPAM_FOREIGN_SCORE", 2),
+		InvisibleScore:  envFloat("SPAM_INVISIBLE_SCORE", 1),
+
+		ModelPath:           os.Getenv("SPAM_MODEL_PATH"),
+		ModelMinDocs:        int(envFloat("SPAM_MODEL_MIN_DOCS", 5)),
+		ClassifierThreshold: envFloat("SPAM_CLASSIFIER_THRESHOLD", 0.9),
+		ClassifierScore:     envFloat("SPAM_CLASSIFIER_SCORE", 3),
+		SubmissionTTL:       utils.GetEnvDurationOrDefault("SPAM_SUBMISSION_TTL", 30*24*time.Hour),
+	}
+}
+
+// envFloat parses a float env value, falling back to defaultValue when unset or invalid.
+func envFloat(key string, defaultValue float64) float64 {
+	if v, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return v
+	}
+	return defaultValue
+}
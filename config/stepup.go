@@ -0,0 +1,42 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"portfolio-backend/utils"
+)
+
+// StepUpConfig controls the second challenge offered to borderline captcha scores.
+type StepUpConfig struct {
+	MinScore          float64 // lowest score eligible for step-up; 0 disables step-up
+	TTL               time.Duration
+	CaptchaProvider   string // interactive captcha used for step-up (e.g. recaptcha_v2)
+	CaptchaSecretKey  string // empty disables the captcha method
+	EmailConfirmation bool
+	ConfirmURL        string // public URL of GET /api/v1/send-email/confirm
+	ConfirmPerHour    int    // confirmation emails per recipient per hour; 0 disables the cap
+	RedirectURL       string // linked from the page shown after confirming; empty shows no link
+}
+
+func LoadStepUpConfig() *StepUpConfig {
+	minScore, _ := strconv.ParseFloat(os.Getenv("STEPUP_MIN_SCORE"), 64)
+	confirmPerHour, err := strconv.Atoi(os.Getenv("STEPUP_CONFIRM_EMAILS_PER_HOUR"))
+	if err != nil {
+		confirmPerHour = 3
+	}
+	serverURL := strings.TrimRight(utils.GetEnvOrDefault("SERVER_URL", "http://localhost:8080"), "/")
+
+	return &StepUpConfig{
+		MinScore:          minScore,
+		TTL:               utils.GetEnvDurationOrDefault("STEPUP_TTL", 30*time.Minute),
+		CaptchaProvider:   utils.GetEnvOrDefault("STEPUP_CAPTCHA_PROVIDER", "recaptcha_v2"),
+		CaptchaSecretKey:  os.Getenv("STEPUP_CAPTCHA_SECRET_KEY"),
+		EmailConfirmation: os.Getenv("STEPUP_EMAIL_CONFIRMATION") == "true",
+		ConfirmURL:        utils.GetEnvOrDefault("STEPUP_CONFIRM_URL", serverURL+"/api/v1/send-email/confirm"),
+		ConfirmPerHour:    confirmPerHour,
+		RedirectURL:       os.Getenv("STEPUP_REDIRECT_URL"),
+	}
+}
@@ -0,0 +1,19 @@
+package config
+
+import "portfolio-backend/utils"
+
+// TracingConfig selects the OpenTelemetry span exporter. The OTLP endpoint and
+// headers use the standard OTEL_EXPORTER_OTLP_* variables read by the exporter.
+type TracingConfig struct {
+	Exporter    string // none, stdout or otlp
+	ServiceName string
+	SampleRatio float64 // share of new traces recorded; incoming sampled traces are always kept
+}
+
+func LoadTracingConfig() *TracingConfig {
+	return &TracingConfig{
+		Exporter:    utils.GetEnvOrDefault("OTEL_TRACES_EXPORTER", "none"),
+		ServiceName: utils.GetEnvOrDefault("OTEL_SERVICE_NAME", "portfolio-backend"),
+		SampleRatio: envFloat("OTEL_TRACES_SAMPLER_ARG", 1),
+	}
+}
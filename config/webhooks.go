@@ -0,0 +1,29 @@
+package config
+
+import (
+	"os"
+	"time"
+
+	"portfolio-backend/utils"
+)
+
+// WebhookConfig holds the outgoing contact event subscriptions. Subscriptions
+// is a JSON array of {"id","url","secret","events"} objects, given inline in
+// WEBHOOK_SUBSCRIPTIONS or in the file named by WEBHOOK_SUBSCRIPTIONS_FILE.
+type WebhookConfig struct {
+	Subscriptions     string
+	SubscriptionsFile string
+	MaxAttempts       int
+	BaseBackoff       time.Duration // doubled after every failed attempt
+	LogTTL            time.Duration // how long deliveries stay available for redelivery
+}
+
+func LoadWebhookConfig() *WebhookConfig {
+	return &WebhookConfig{
+		Subscriptions:     os.Getenv("WEBHOOK_SUBSCRIPTIONS"),
+		SubscriptionsFile: os.Getenv("WEBHOOK_SUBSCRIPTIONS_FILE"),
+		MaxAttempts:       int(envFloat("WEBHOOK_MAX_ATTEMPTS", 6)),
+		BaseBackoff:       utils.GetEnvDurationOrDefault("WEBHOOK_BASE_BACKOFF", 30*time.Second),
+		LogTTL:            utils.GetEnvDurationOrDefault("WEBHOOK_LOG_TTL", 168*time.Hour),
+	}
+}
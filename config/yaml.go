@@ -0,0 +1,75 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFile is read when present; CONFIG_FILE names another one.
+const defaultConfigFile = "config.yaml"
+
+// loadYAML reads the optional YAML config file and sets every value that the
+// environment (including .env) does not already define. Keys are either env
+// names (SMTP_HOST: ...) or nested sections (smtp: {host: ...}); lists become
+// comma-separated values.
+func loadYAML() error {
+	path, explicit := os.LookupEnv("CONFIG_FILE")
+	if !explicit || path == "" {
+		path = defaultConfigFile
+	}
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	values := map[string]string{}
+	if err := flattenYAML(values, nil, doc); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	for key, value := range values {
+		if _, set := os.LookupEnv(key); !set {
+			os.Setenv(key, value)
+		}
+	}
+	return nil
+}
+
+func flattenYAML(out map[string]string, path []string, node interface{}) error {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if err := flattenYAML(out, append(path[:len(path):len(path)], k), child); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]interface{}, []interface{}:
+				return fmt.Errorf("%s: lists may only contain scalar values", envKeyName(path...))
+			}
+			items = append(items, fmt.Sprint(item))
+		}
+		out[envKeyName(path...)] = strings.Join(items, ",")
+	case nil:
+		out[envKeyName(path...)] = ""
+	default:
+		out[envKeyName(path...)] = fmt.Sprint(v)
+	}
+	return nil
+}
@@ -1,37 +1,29 @@
 package main
 
 import (
+	"context"
 	"log"
+	"log/slog"
 	"net/http"
-	"os"
 
 	"portfolio-backend/app/providers"
 
 	"portfolio-backend/bootstrap"
 )
 
-func getEnvOrDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
-
 func main() {
-	bootstrap.LoadEnv()
-
-	env := getEnvOrDefault("APP_ENV", "local")
+	env := bootstrap.LoadEnv()
+	cfg := bootstrap.LoadConfig()
+	bootstrap.SetupLogging(cfg.Logging)
+	env.Log()
+	slog.Info("Configuration loaded", cfg.Summary()...)
+	shutdownTracing := bootstrap.SetupTracing(cfg.Tracing)
+	defer shutdownTracing(context.Background())
 
-	app := providers.NewAppServiceProvider()
+	app := providers.NewAppServiceProvider(cfg)
 
 	// Start server
-	// Prefer the PORT that hosting provides; fallback to SERVER_PORT then 8080.
-	serverPort := os.Getenv("PORT")
-	if serverPort == "" {
-		serverPort = getEnvOrDefault("SERVER_PORT", "8080")
-	}
-	serverURL := getEnvOrDefault("SERVER_URL", serverPort)
-	log.Printf("Server [%s] started on %s", env, serverURL)
+	slog.Info("Server started", "env", cfg.App.Env, "url", cfg.App.ServerURL)
 
-	log.Fatal(http.ListenAndServe(":"+serverPort, app.Handler()))
+	log.Fatal(http.ListenAndServe(":"+cfg.App.Port, app.Handler()))
 }
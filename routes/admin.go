@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"net/http"
+
+	api_controllers "portfolio-backend/app/controllers/api"
+	"portfolio-backend/app/middlewares"
+)
+
+func RegisterAdminRoutes(mux *http.ServeMux, adminAPIKey string, captchaController *api_controllers.CaptchaController, spamController *api_controllers.SpamController, webhookController *api_controllers.WebhookController) {
+	// Admin routes are disabled unless ADMIN_API_KEY is set
+	withAdminKey := middlewares.APIKeyMiddleware(adminAPIKey)
+
+	mux.Handle("GET /admin/captcha-scores", withAdminKey(http.HandlerFunc(captchaController.ScoreHistogram)))
+	mux.Handle("POST /admin/spam/train", withAdminKey(middlewares.MaxBodyBytes(256<<10)(http.HandlerFunc(spamController.Train))))
+	mux.Handle("GET /admin/spam/model", withAdminKey(http.HandlerFunc(spamController.Model)))
+	mux.Handle("GET /admin/webhooks/deliveries", withAdminKey(http.HandlerFunc(webhookController.Deliveries)))
+	mux.Handle("POST /admin/webhooks/redeliver", withAdminKey(middlewares.MaxBodyBytes(4<<10)(http.HandlerFunc(webhookController.Redeliver))))
+}
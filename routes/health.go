@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"net/http"
+
+	api_controllers "portfolio-backend/app/controllers/api"
+	"portfolio-backend/app/middlewares"
+)
+
+// HealthPaths are served without the global rate limiter so platform health
+// checks never get a 429.
+var HealthPaths = []string{"/healthz", "/readyz"}
+
+func RegisterHealthRoutes(mux *http.ServeMux, adminAPIKey string, healthController *api_controllers.HealthController) {
+	mux.HandleFunc("GET /healthz", healthController.Healthz)
+	mux.HandleFunc("GET /readyz", healthController.Readyz)
+	mux.Handle("GET /status", middlewares.APIKeyMiddleware(adminAPIKey)(http.HandlerFunc(healthController.Status)))
+}
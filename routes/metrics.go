@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"net/http"
+
+	"portfolio-backend/app/middlewares"
+	"portfolio-backend/config"
+	"portfolio-backend/services/metrics"
+)
+
+func RegisterMetricsRoutes(mux *http.ServeMux, cfg *config.MetricsConfig) {
+	if !cfg.Enabled {
+		return
+	}
+
+	handler := metrics.Handler()
+	// Scrapers authenticate with X-API-KEY when METRICS_API_KEY is set
+	if cfg.APIKey != "" {
+		handler = middlewares.APIKeyMiddleware(cfg.APIKey)(handler)
+	}
+	mux.Handle("GET /metrics", handler)
+}
@@ -4,21 +4,55 @@ import (
 	"net/http"
 	api_controllers "portfolio-backend/app/controllers/api"
 	"portfolio-backend/app/middlewares"
-	"portfolio-backend/services/redis"
+	"portfolio-backend/config"
 	"time"
 )
 
-func RegisterWebRoutes(mux *http.ServeMux, emailController *api_controllers.EmailController, discordController *api_controllers.DiscordController) {
-	rateLimiter := redis.NewUpstashService()
-	// Create the middleware (e.g., 5 requests per second, burst 10)
+// APIPrefix is where the versioned public API is served.
+const APIPrefix = "/api/v1"
+
+func RegisterWebRoutes(mux *http.ServeMux, apiCfg *config.APIConfig, rateLimitCfg *config.RateLimitingConfig, rateLimiter middlewares.RateLimiterService, emailController *api_controllers.EmailController, discordController *api_controllers.DiscordController) {
+	// Create the middleware (e.g., 5 requests per second, burst 10); local
+	// development skips the per-route limits
 	withRateLimit := func(baseKey string, rps, burst int, ttl time.Duration, handler http.HandlerFunc) http.Handler {
+		if !rateLimitCfg.PerRoute {
+			return handler
+		}
 		return middlewares.RateLimitMiddlewareWithKey(rateLimiter, baseKey, rps, burst, ttl)(handler)
 	}
 
+	// Per-route request body limits; the Discord proxy applies its own configurable cap
+	withBodyLimit := func(n int64, handler http.Handler) http.Handler {
+		return middlewares.MaxBodyBytes(n)(handler)
+	}
+
+	// Each API route is served under /api/v1 and, until API_LEGACY_ROUTES is
+	// turned off, at its old unversioned path with Deprecation and Sunset
+	// headers. Both share one handler, so they share rate limits too
+	api := func(method, path string, handler http.Handler) {
+		mux.Handle(method+" "+APIPrefix+path, handler)
+		if apiCfg.LegacyRoutes {
+			mux.Handle(method+" "+path, middlewares.Deprecated(APIPrefix+path, apiCfg.DeprecatedAt, apiCfg.Sunset)(handler))
+		}
+	}
+
 	oneHour := time.Hour
 
 	// Wrap your handlers with the middleware
-	mux.Handle("/send-email", withRateLimit("send_email", 1, 1, oneHour, emailController.SendEmail))
-	mux.Handle("/discord-webhook", withRateLimit("discord_webhook", 1, 1, oneHour, discordController.SendWebhook))
-	mux.HandleFunc("/preview-email", emailController.PreviewEmail)
+	api("POST", "/send-email", withBodyLimit(64<<10, withRateLimit("send_email", 1, 1, oneHour, emailController.SendEmail)))
+	api("POST", "/discord-webhook", withRateLimit("discord_webhook", 1, 1, oneHour, discordController.SendWebhook))
+	for _, target := range discordController.Targets.List() {
+		if target.Name == "default" {
+			continue
+		}
+		// Each named target is rate limited separately: RateLimit requests an
+		// hour, in windows that let Burst of them through at once
+		window := time.Hour / time.Duration(target.RateLimit) * time.Duration(target.Burst)
+		api("POST", "/discord-webhook/"+target.Name, withRateLimit("discord_webhook_"+target.Name, 1, target.Burst, window, discordController.SendToTarget(target.Name)))
+	}
+	api("GET", "/send-email/form-token", withRateLimit("send_email_form_token", 30, 1, oneHour, emailController.FormToken))
+	api("POST", "/send-email/challenge", withBodyLimit(8<<10, withRateLimit("send_email_challenge", 5, 1, oneHour, emailController.CompleteChallenge)))
+	api("GET", "/send-email/confirm", withRateLimit("send_email_confirm_page", 20, 1, oneHour, emailController.ConfirmPage))
+	api("POST", "/send-email/confirm", withBodyLimit(8<<10, withRateLimit("send_email_confirm", 5, 1, oneHour, emailController.ConfirmChallenge)))
+	mux.HandleFunc("GET /preview-email", emailController.PreviewEmail)
 }
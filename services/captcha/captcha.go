@@ -0,0 +1,73 @@
+package captcha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrScoreTooLow is wrapped by Verify errors caused only by the score threshold,
+// so callers can offer a step-up challenge instead of a hard rejection.
+var ErrScoreTooLow = errors.New("score too low")
+
+// Supported provider identifiers (CAPTCHA_PROVIDER).
+const (
+	ProviderRecaptchaV3 = "recaptcha_v3"
+	ProviderRecaptchaV2 = "recaptcha_v2"
+	ProviderHCaptcha    = "hcaptcha"
+	ProviderTurnstile   = "turnstile"
+)
+
+// Result is the provider-agnostic outcome of a token verification.
+type Result struct {
+	Provider    string
+	Success     bool
+	Scored      bool // the provider returns a score (reCAPTCHA v3)
+	Score       float64
+	Action      string
+	Hostname    string
+	ChallengeTS string
+	ErrorCodes  []string
+}
+
+// Rule holds the expectations a token must meet for a given route.
+type Rule struct {
+	Action   string  // expected action; empty skips the check
+	MinScore float64 // minimum score; 0 skips the check (ignored by providers without scores)
+}
+
+// Verifier verifies a client-side captcha token.
+// A non-nil error means the token must be rejected; the Result is still
+// returned when available so callers can log the score.
+type Verifier interface {
+	Provider() string
+	Verify(ctx context.Context, token, remoteIP string, rule Rule) (*Result, error)
+}
+
+// New returns the verifier for the given provider.
+func New(provider, secret string) (Verifier, error) {
+	switch strings.ToLower(strings.TrimSpace(provider)) {
+	case "", ProviderRecaptchaV3:
+		return NewRecaptchaV3(secret), nil
+	case ProviderRecaptchaV2:
+		return NewRecaptchaV2(secret), nil
+	case ProviderHCaptcha:
+		return NewHCaptcha(secret), nil
+	case ProviderTurnstile:
+		return NewTurnstile(secret), nil
+	default:
+		return nil, fmt.Errorf("unknown captcha provider %q", provider)
+	}
+}
+
+// checkRule applies the action and score expectations to a successful result.
+func checkRule(res *Result, rule Rule, hasAction, hasScore bool) error {
+	if hasAction && rule.Action != "" && res.Action != rule.Action {
+		return fmt.Errorf("%s action mismatch (got=%s expected=%s)", res.Provider, res.Action, rule.Action)
+	}
+	if hasScore && rule.MinScore > 0 && res.Score < rule.MinScore {
+		return fmt.Errorf("%s %w: %f < %f", res.Provider, ErrScoreTooLow, res.Score, rule.MinScore)
+	}
+	return nil
+}
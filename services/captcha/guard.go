@@ -0,0 +1,156 @@
+package captcha
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"portfolio-backend/services/metrics"
+	"portfolio-backend/services/tracing"
+)
+
+// GuardStore is the persistence needed by GuardedVerifier (implemented by the Redis service).
+type GuardStore interface {
+	// SetOnce stores key with ttl and reports whether it was newly set.
+	SetOnce(key string, ttl time.Duration) (bool, error)
+	// IncrHashField increments field in the hash at key.
+	IncrHashField(key, field string) error
+	// HashFields returns all fields of the hash at key.
+	HashFields(key string) (map[string]string, error)
+}
+
+// GuardOptions configures the checks GuardedVerifier adds on top of the provider.
+type GuardOptions struct {
+	AllowedHostnames []string      // empty allows any hostname
+	MaxTokenAge      time.Duration // 0 skips the challenge timestamp check
+	ReplayTTL        time.Duration // how long used token hashes are remembered; 0 disables replay protection
+}
+
+// GuardedVerifier wraps a Verifier with hostname, token age and replay checks,
+// and records a score histogram per action for tokens that passed them.
+type GuardedVerifier struct {
+	Verifier Verifier
+	Store    GuardStore
+	Options  GuardOptions
+	now      func() time.Time
+}
+
+func NewGuardedVerifier(v Verifier, store GuardStore, opts GuardOptions) *GuardedVerifier {
+	return &GuardedVerifier{Verifier: v, Store: store, Options: opts, now: time.Now}
+}
+
+func (g *GuardedVerifier) Provider() string {
+	return g.Verifier.Provider()
+}
+
+// Verify checks the token with the provider, then the hostname, token age and
+// replay guards, all under one captcha.Verify span. A score below the rule's
+// minimum is only reported (as ErrScoreTooLow) for tokens that passed every
+// guard, so foreign, stale or replayed tokens can never be stepped up.
+func (g *GuardedVerifier) Verify(ctx context.Context, token, remoteIP string, rule Rule) (*Result, error) {
+	ctx, span := tracing.Start(ctx, "captcha.Verify",
+		attribute.String("captcha.provider", g.Provider()),
+		attribute.String("captcha.action", rule.Action),
+	)
+	res, err := g.verify(ctx, token, remoteIP, rule)
+	if res != nil {
+		span.SetAttributes(attribute.Bool("captcha.success", res.Success), attribute.Float64("captcha.score", res.Score))
+	}
+	tracing.End(span, err)
+	return res, err
+}
+
+func (g *GuardedVerifier) verify(ctx context.Context, token, remoteIP string, rule Rule) (*Result, error) {
+	res, err := g.Verifier.Verify(ctx, token, remoteIP, rule)
+	if err != nil && !errors.Is(err, ErrScoreTooLow) {
+		return res, err
+	}
+	scoreErr := err
+
+	if len(g.Options.AllowedHostnames) > 0 && !hostnameAllowed(res.Hostname, g.Options.AllowedHostnames) {
+		return res, fmt.Errorf("%s hostname not allowed: %q", res.Provider, res.Hostname)
+	}
+
+	if g.Options.MaxTokenAge > 0 {
+		ts, err := time.Parse(time.RFC3339, res.ChallengeTS)
+		if err != nil {
+			return res, fmt.Errorf("%s invalid challenge timestamp %q", res.Provider, res.ChallengeTS)
+		}
+		if age := g.now().Sub(ts); age > g.Options.MaxTokenAge {
+			return res, fmt.Errorf("%s token too old: %s > %s", res.Provider, age.Round(time.Second), g.Options.MaxTokenAge)
+		}
+	}
+
+	if g.Options.ReplayTTL > 0 && g.Store != nil {
+		fresh, err := g.Store.SetOnce(tokenKey(token), g.Options.ReplayTTL)
+		if err != nil {
+			return res, fmt.Errorf("captcha replay check failed: %w", err)
+		}
+		if !fresh {
+			return res, fmt.Errorf("%s token already used", res.Provider)
+		}
+	}
+
+	if res.Scored {
+		g.recordScore(ctx, res, rule)
+	}
+	return res, scoreErr
+}
+
+// ScoreHistogram returns the recorded score buckets ("0.0".."1.0") for an action.
+func (g *GuardedVerifier) ScoreHistogram(action string) (map[string]string, error) {
+	if g.Store == nil {
+		return map[string]string{}, nil
+	}
+	return g.Store.HashFields(histogramKey(action))
+}
+
+// recordScore adds the score to the action's histogram in 0.1 buckets.
+// Failures are logged only; analytics must never block a submission.
+func (g *GuardedVerifier) recordScore(ctx context.Context, res *Result, rule Rule) {
+	action := res.Action
+	if action == "" {
+		action = rule.Action
+	}
+	metrics.CaptchaScores.WithLabelValues(res.Provider, action).Observe(res.Score)
+
+	if g.Store == nil {
+		return
+	}
+	bucket := fmt.Sprintf("%.1f", math.Floor(res.Score*10+1e-9)/10)
+	if err := g.Store.IncrHashField(histogramKey(action), bucket); err != nil {
+		slog.WarnContext(ctx, "captcha score histogram update failed", "action", action, "error", err)
+	}
+}
+
+func hostnameAllowed(hostname string, allowed []string) bool {
+	for _, h := range allowed {
+		if strings.EqualFold(h, hostname) {
+			return true
+		}
+	}
+	return false
+}
+
+func tokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "captcha:token:" + hex.EncodeToString(sum[:])
+}
+
+func histogramKey(action string) string {
+	if action == "" {
+		action = "none"
+	}
+	return "captcha:scores:" + action
+}
+
+// Ensure GuardedVerifier implements Verifier
+var _ Verifier = (*GuardedVerifier)(nil)
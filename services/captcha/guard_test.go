@@ -0,0 +1,168 @@
+package captcha
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryGuardStore is an in-memory GuardStore.
+type memoryGuardStore struct {
+	mu     sync.Mutex
+	keys   map[string]bool
+	hashes map[string]map[string]string
+}
+
+func newMemoryGuardStore() *memoryGuardStore {
+	return &memoryGuardStore{keys: map[string]bool{}, hashes: map[string]map[string]string{}}
+}
+
+func (m *memoryGuardStore) SetOnce(key string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.keys[key] {
+		return false, nil
+	}
+	m.keys[key] = true
+	return true, nil
+}
+
+func (m *memoryGuardStore) IncrHashField(key, field string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.hashes[key] == nil {
+		m.hashes[key] = map[string]string{}
+	}
+	n, _ := strconv.Atoi(m.hashes[key][field])
+	m.hashes[key][field] = strconv.Itoa(n + 1)
+	return nil
+}
+
+func (m *memoryGuardStore) HashFields(key string) (map[string]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.hashes[key], nil
+}
+
+func TestGuardedVerifier(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	fresh := now.Add(-time.Minute).Format(time.RFC3339)
+	opts := GuardOptions{AllowedHostnames: []string{"example.com"}, MaxTokenAge: 5 * time.Minute, ReplayTTL: time.Hour}
+	rule := Rule{Action: "send_email", MinScore: 0.5}
+
+	tests := []struct {
+		name         string
+		newVerifier  func(secret string) *SiteVerifier
+		reply        siteVerifyResponse
+		uses         int // verifications of the same token; the last one is checked
+		wantErr      bool
+		wantScoreErr bool
+		wantRecorded int // scores in the histogram afterwards
+	}{
+		{
+			name:         "passes",
+			newVerifier:  NewRecaptchaV3,
+			reply:        siteVerifyResponse{Success: true, Score: 0.9, Action: "send_email", Hostname: "example.com", ChallengeTS: fresh},
+			uses:         1,
+			wantRecorded: 1,
+		},
+		{
+			name:         "hostname is compared case-insensitively",
+			newVerifier:  NewRecaptchaV3,
+			reply:        siteVerifyResponse{Success: true, Score: 0.9, Action: "send_email", Hostname: "EXAMPLE.com", ChallengeTS: fresh},
+			uses:         1,
+			wantRecorded: 1,
+		},
+		{
+			name:         "low score after guards",
+			newVerifier:  NewRecaptchaV3,
+			reply:        siteVerifyResponse{Success: true, Score: 0.2, Action: "send_email", Hostname: "example.com", ChallengeTS: fresh},
+			uses:         1,
+			wantErr:      true,
+			wantScoreErr: true,
+			wantRecorded: 1,
+		},
+		{
+			name:        "hostname not allowed",
+			newVerifier: NewRecaptchaV3,
+			reply:       siteVerifyResponse{Success: true, Score: 0.9, Action: "send_email", Hostname: "evil.example", ChallengeTS: fresh},
+			uses:        1,
+			wantErr:     true,
+		},
+		{
+			name:        "low score on a foreign hostname is not eligible for step-up",
+			newVerifier: NewRecaptchaV3,
+			reply:       siteVerifyResponse{Success: true, Score: 0.2, Action: "send_email", Hostname: "evil.example", ChallengeTS: fresh},
+			uses:        1,
+			wantErr:     true,
+		},
+		{
+			name:        "token too old",
+			newVerifier: NewRecaptchaV3,
+			reply:       siteVerifyResponse{Success: true, Score: 0.9, Action: "send_email", Hostname: "example.com", ChallengeTS: now.Add(-time.Hour).Format(time.RFC3339)},
+			uses:        1,
+			wantErr:     true,
+		},
+		{
+			name:        "invalid challenge timestamp",
+			newVerifier: NewRecaptchaV3,
+			reply:       siteVerifyResponse{Success: true, Score: 0.9, Action: "send_email", Hostname: "example.com", ChallengeTS: "yesterday"},
+			uses:        1,
+			wantErr:     true,
+		},
+		{
+			name:         "replayed token",
+			newVerifier:  NewRecaptchaV3,
+			reply:        siteVerifyResponse{Success: true, Score: 0.9, Action: "send_email", Hostname: "example.com", ChallengeTS: fresh},
+			uses:         2,
+			wantErr:      true,
+			wantRecorded: 1,
+		},
+		{
+			name:        "providers without scores are not recorded",
+			newVerifier: NewTurnstile,
+			reply:       siteVerifyResponse{Success: true, Action: "send_email", Hostname: "example.com", ChallengeTS: fresh},
+			uses:        1,
+		},
+		{
+			name:        "provider failure",
+			newVerifier: NewHCaptcha,
+			reply:       siteVerifyResponse{Success: false, ErrorCodes: []string{"invalid-input-response"}},
+			uses:        1,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := tt.newVerifier("secret")
+			v.URL = siteVerifyServer(t, tt.reply).URL
+			store := newMemoryGuardStore()
+			g := NewGuardedVerifier(v, store, opts)
+			g.now = func() time.Time { return now }
+
+			var err error
+			for i := 0; i < tt.uses; i++ {
+				_, err = g.Verify(context.Background(), "token", "203.0.113.7", rule)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify error = %v, want error %v", err, tt.wantErr)
+			}
+			if got := errors.Is(err, ErrScoreTooLow); got != tt.wantScoreErr {
+				t.Errorf("errors.Is(err, ErrScoreTooLow) = %v for %v", got, err)
+			}
+
+			buckets, _ := g.ScoreHistogram(rule.Action)
+			recorded := 0
+			for _, n := range buckets {
+				c, _ := strconv.Atoi(n)
+				recorded += c
+			}
+			if recorded != tt.wantRecorded {
+				t.Errorf("recorded %d scores, want %d (%v)", recorded, tt.wantRecorded, buckets)
+			}
+		})
+	}
+}
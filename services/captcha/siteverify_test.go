@@ -0,0 +1,108 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// siteVerifyServer answers every siteverify POST with reply, after checking
+// the form the adapter sent.
+func siteVerifyServer(t *testing.T, reply siteVerifyResponse) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm: %v", err)
+		}
+		if r.PostForm.Get("secret") != "secret" || r.PostForm.Get("response") != "token" || r.PostForm.Get("remoteip") != "203.0.113.7" {
+			t.Errorf("form = %v", r.PostForm)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(reply)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSiteVerifierProviders(t *testing.T) {
+	providers := []func(secret string) *SiteVerifier{NewRecaptchaV3, NewRecaptchaV2, NewHCaptcha, NewTurnstile}
+
+	tests := []struct {
+		name      string
+		reply     siteVerifyResponse
+		rule      Rule
+		failed    bool // rejected by every provider
+		actionErr bool // rejected by providers that report the action
+		scoreErr  bool // ErrScoreTooLow from providers with scores
+	}{
+		{
+			name:  "success",
+			reply: siteVerifyResponse{Success: true, Score: 0.9, Action: "send_email", Hostname: "example.com"},
+			rule:  Rule{Action: "send_email", MinScore: 0.5},
+		},
+		{
+			name:   "failure",
+			reply:  siteVerifyResponse{Success: false, ErrorCodes: []string{"invalid-input-response"}},
+			rule:   Rule{Action: "send_email", MinScore: 0.5},
+			failed: true,
+		},
+		{
+			name:      "wrong action",
+			reply:     siteVerifyResponse{Success: true, Score: 0.9, Action: "login"},
+			rule:      Rule{Action: "send_email", MinScore: 0.5},
+			actionErr: true,
+		},
+		{
+			name:     "low score",
+			reply:    siteVerifyResponse{Success: true, Score: 0.2, Action: "send_email"},
+			rule:     Rule{Action: "send_email", MinScore: 0.5},
+			scoreErr: true,
+		},
+	}
+	for _, newVerifier := range providers {
+		for _, tt := range tests {
+			v := newVerifier("secret")
+			t.Run(v.Name+" "+tt.name, func(t *testing.T) {
+				v.URL = siteVerifyServer(t, tt.reply).URL
+				wantScoreErr := tt.scoreErr && v.HasScore
+				wantErr := tt.failed || tt.actionErr && v.HasAction || wantScoreErr
+
+				res, err := v.Verify(context.Background(), "token", "203.0.113.7", tt.rule)
+				if (err != nil) != wantErr {
+					t.Fatalf("Verify error = %v, want error %v", err, wantErr)
+				}
+				if got := errors.Is(err, ErrScoreTooLow); got != wantScoreErr {
+					t.Errorf("errors.Is(err, ErrScoreTooLow) = %v for %v", got, err)
+				}
+				if res == nil || res.Provider != v.Name || res.Success != tt.reply.Success || res.Scored != v.HasScore {
+					t.Errorf("Result = %+v", res)
+				}
+			})
+		}
+	}
+}
+
+func TestSiteVerifierRejectsBeforeCalling(t *testing.T) {
+	tests := []struct {
+		name, secret, token string
+	}{
+		{"no secret", "", "token"},
+		{"empty token", "secret", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Error("siteverify called")
+			}))
+			defer srv.Close()
+			v := NewRecaptchaV3(tt.secret)
+			v.URL = srv.URL
+			if _, err := v.Verify(context.Background(), tt.token, "", Rule{}); err == nil {
+				t.Error("Verify error = nil, want an error")
+			}
+		})
+	}
+}
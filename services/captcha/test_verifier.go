@@ -0,0 +1,69 @@
+package captcha
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Tokens accepted in test mode. hCaptcha and Turnstile publish these dummy
+// response tokens for their test keys. Google publishes test site and secret
+// keys instead, whose siteverify accepts any response, so RecaptchaTestToken
+// is this app's own placeholder for frontends using those keys.
+const (
+	RecaptchaTestToken = "recaptcha-test-token"
+	HCaptchaTestToken  = "10000000-aaaa-bbbb-cccc-000000000001"
+	TurnstileTestToken = "XXXX.DUMMY.TOKEN.XXXX"
+)
+
+// TestVerifier accepts a fixed set of dummy tokens without any network call,
+// so end-to-end tests can run offline. Each token maps to the score it yields.
+type TestVerifier struct {
+	Name   string
+	Tokens map[string]float64
+}
+
+// NewTestVerifier returns a TestVerifier for provider that accepts the
+// test tokens above plus any extra tokens given.
+func NewTestVerifier(provider string, extra map[string]float64) *TestVerifier {
+	tokens := map[string]float64{
+		RecaptchaTestToken: 0.9,
+		HCaptchaTestToken:  0.9,
+		TurnstileTestToken: 0.9,
+	}
+	for t, s := range extra {
+		tokens[t] = s
+	}
+	if provider == "" {
+		provider = ProviderRecaptchaV3
+	}
+	return &TestVerifier{Name: provider, Tokens: tokens}
+}
+
+func (v *TestVerifier) Provider() string {
+	return v.Name
+}
+
+// Verify accepts only the known dummy tokens and echoes the expected action.
+func (v *TestVerifier) Verify(ctx context.Context, token, remoteIP string, rule Rule) (*Result, error) {
+	score, ok := v.Tokens[token]
+	if !ok {
+		return nil, fmt.Errorf("%s (test mode) unknown token", v.Name)
+	}
+	res := &Result{
+		Provider:    v.Name,
+		Success:     true,
+		Scored:      v.Name == ProviderRecaptchaV3,
+		Score:       score,
+		Action:      rule.Action,
+		Hostname:    "localhost",
+		ChallengeTS: time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := checkRule(res, rule, true, true); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// Ensure TestVerifier implements Verifier
+var _ Verifier = (*TestVerifier)(nil)
@@ -0,0 +1,201 @@
+package classifier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Labels accepted by Train.
+const (
+	LabelSpam = "spam"
+	LabelHam  = "ham"
+)
+
+var ErrUnknownLabel = errors.New("label must be spam or ham")
+
+// Model is the persisted state of the classifier: document and token counts per class.
+type Model struct {
+	Docs   map[string]int            `json:"docs"`
+	Tokens map[string]int            `json:"tokens"`
+	Counts map[string]map[string]int `json:"counts"`
+}
+
+func newModel() *Model {
+	return &Model{
+		Docs:   map[string]int{LabelSpam: 0, LabelHam: 0},
+		Tokens: map[string]int{LabelSpam: 0, LabelHam: 0},
+		Counts: map[string]map[string]int{LabelSpam: {}, LabelHam: {}},
+	}
+}
+
+// Classifier is a multinomial naive Bayes spam classifier over tokenized text,
+// persisted as JSON at Path.
+type Classifier struct {
+	Path    string
+	MinDocs int // documents needed in each class before predictions are made
+
+	mu    sync.RWMutex
+	model *Model
+}
+
+// Load reads the model at path, starting empty when the file does not exist yet.
+func Load(path string, minDocs int) (*Classifier, error) {
+	c := &Classifier{Path: path, MinDocs: minDocs, model: newModel()}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := newModel()
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("invalid spam model %s: %w", path, err)
+	}
+	m.fill()
+	c.model = m
+	return c, nil
+}
+
+// fill creates the maps and per-label entries a model file may lack (null
+// or missing keys), so training never writes to a nil map.
+func (m *Model) fill() {
+	if m.Docs == nil {
+		m.Docs = map[string]int{}
+	}
+	if m.Tokens == nil {
+		m.Tokens = map[string]int{}
+	}
+	if m.Counts == nil {
+		m.Counts = map[string]map[string]int{}
+	}
+	for _, label := range []string{LabelSpam, LabelHam} {
+		if m.Counts[label] == nil {
+			m.Counts[label] = map[string]int{}
+		}
+	}
+}
+
+// Train adds text to the given class.
+func (c *Classifier) Train(text, label string) error {
+	return c.update(text, label, 1)
+}
+
+// Untrain removes text previously trained under label (used when a label is corrected).
+func (c *Classifier) Untrain(text, label string) error {
+	return c.update(text, label, -1)
+}
+
+func (c *Classifier) update(text, label string, delta int) error {
+	if label != LabelSpam && label != LabelHam {
+		return ErrUnknownLabel
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counts := c.model.Counts[label]
+	for _, tok := range Tokenize(text) {
+		counts[tok] += delta
+		if counts[tok] <= 0 {
+			delete(counts, tok)
+		}
+		c.model.Tokens[label] = max(c.model.Tokens[label]+delta, 0)
+	}
+	c.model.Docs[label] = max(c.model.Docs[label]+delta, 0)
+	return nil
+}
+
+// SpamProbability returns P(spam|text). ok is false until both classes have MinDocs documents.
+func (c *Classifier) SpamProbability(text string) (float64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	m := c.model
+	if m.Docs[LabelSpam] < c.MinDocs || m.Docs[LabelHam] < c.MinDocs {
+		return 0, false
+	}
+
+	vocab := map[string]struct{}{}
+	for _, counts := range m.Counts {
+		for tok := range counts {
+			vocab[tok] = struct{}{}
+		}
+	}
+	v := float64(len(vocab))
+	totalDocs := float64(m.Docs[LabelSpam] + m.Docs[LabelHam])
+
+	logProb := func(label string, tokens []string) float64 {
+		lp := math.Log(float64(m.Docs[label]) / totalDocs)
+		denom := float64(m.Tokens[label]) + v
+		for _, tok := range tokens {
+			// Laplace smoothing
+			lp += math.Log((float64(m.Counts[label][tok]) + 1) / denom)
+		}
+		return lp
+	}
+
+	tokens := Tokenize(text)
+	spam := logProb(LabelSpam, tokens)
+	ham := logProb(LabelHam, tokens)
+	// P(spam) = 1 / (1 + e^(ham-spam)), computed in log space to avoid underflow
+	return 1 / (1 + math.Exp(ham-spam)), true
+}
+
+// Stats returns document and vocabulary counts per class. The maps are
+// copies, so callers can encode them while training continues.
+func (c *Classifier) Stats() map[string]interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return map[string]interface{}{
+		"docs":       maps.Clone(c.model.Docs),
+		"tokens":     maps.Clone(c.model.Tokens),
+		"vocabulary": map[string]int{LabelSpam: len(c.model.Counts[LabelSpam]), LabelHam: len(c.model.Counts[LabelHam])},
+		"ready":      c.model.Docs[LabelSpam] >= c.MinDocs && c.model.Docs[LabelHam] >= c.MinDocs,
+	}
+}
+
+// Save writes the model to Path atomically.
+func (c *Classifier) Save() error {
+	c.mu.RLock()
+	data, err := json.Marshal(c.model)
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0o755); err != nil {
+		return err
+	}
+	tmp := c.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.Path)
+}
+
+var urlPattern = regexp.MustCompile(`(?i)(https?://|www\.)\S+`)
+
+// Tokenize lowercases text, collapses URLs to a single marker token and
+// splits on anything that is not a letter or digit.
+func Tokenize(text string) []string {
+	text = urlPattern.ReplaceAllString(strings.ToLower(text), " __url__ ")
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	tokens := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if n := len([]rune(f)); n >= 2 && n <= 30 {
+			tokens = append(tokens, f)
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,200 @@
+package classifier
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"lowercases and splits on punctuation", "Hello, World!", []string{"hello", "world"}},
+		{"keeps digits and underscores", "order_id 42x", []string{"order_id", "42x"}},
+		{"collapses urls", "visit https://spam.example/buy?x=1 now", []string{"visit", "__url__", "now"}},
+		{"collapses www urls", "see www.example.com", []string{"see", "__url__"}},
+		{"drops single characters", "a b cd", []string{"cd"}},
+		{"drops tokens over 30 characters", "ok abcdefghijklmnopqrstuvwxyzabcde", []string{"ok"}},
+		{"keeps unicode letters", "Größe café", []string{"größe", "café"}},
+		{"empty text", "  ...  ", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func trained(t *testing.T, minDocs int, docs map[string][]string) *Classifier {
+	t.Helper()
+	c, err := Load(filepath.Join(t.TempDir(), "model.json"), minDocs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for label, texts := range docs {
+		for _, text := range texts {
+			if err := c.Train(text, label); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return c
+}
+
+func TestSpamProbability(t *testing.T) {
+	c := trained(t, 2, map[string][]string{
+		LabelSpam: {"cheap pills buy now", "buy cheap watches now", "win money now click https://x.example"},
+		LabelHam:  {"hello I liked your portfolio", "can we schedule a call about the project", "your project looks great"},
+	})
+
+	tests := []struct {
+		name     string
+		text     string
+		wantSpam bool
+	}{
+		{"spam words", "buy cheap pills now", true},
+		{"links", "click https://y.example to win", true},
+		{"ham words", "I liked the project, can we schedule a call", false},
+		{"portfolio feedback", "hello, great portfolio", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := c.SpamProbability(tt.text)
+			if !ok {
+				t.Fatal("classifier not ready")
+			}
+			if got := p >= 0.5; got != tt.wantSpam {
+				t.Errorf("SpamProbability(%q) = %.3f, want spam=%v", tt.text, p, tt.wantSpam)
+			}
+		})
+	}
+}
+
+func TestSpamProbabilityNotReady(t *testing.T) {
+	tests := []struct {
+		name string
+		docs map[string][]string
+	}{
+		{"empty", nil},
+		{"spam only", map[string][]string{LabelSpam: {"buy now", "cheap pills"}}},
+		{"too few ham", map[string][]string{LabelSpam: {"buy now", "cheap pills"}, LabelHam: {"hello there"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := trained(t, 2, tt.docs)
+			if _, ok := c.SpamProbability("buy now"); ok {
+				t.Error("SpamProbability ok = true, want false")
+			}
+		})
+	}
+}
+
+func TestRetrain(t *testing.T) {
+	const text = "buy cheap pills now"
+	tests := []struct {
+		name       string
+		steps      func(c *Classifier) error
+		wantDocs   map[string]int
+		wantTokens map[string]int
+	}{
+		{
+			name:       "train",
+			steps:      func(c *Classifier) error { return c.Train(text, LabelSpam) },
+			wantDocs:   map[string]int{LabelSpam: 1, LabelHam: 0},
+			wantTokens: map[string]int{LabelSpam: 4, LabelHam: 0},
+		},
+		{
+			name: "correct spam to ham",
+			steps: func(c *Classifier) error {
+				if err := c.Train(text, LabelSpam); err != nil {
+					return err
+				}
+				if err := c.Untrain(text, LabelSpam); err != nil {
+					return err
+				}
+				return c.Train(text, LabelHam)
+			},
+			wantDocs:   map[string]int{LabelSpam: 0, LabelHam: 1},
+			wantTokens: map[string]int{LabelSpam: 0, LabelHam: 4},
+		},
+		{
+			name:       "untrain never goes negative",
+			steps:      func(c *Classifier) error { return c.Untrain(text, LabelHam) },
+			wantDocs:   map[string]int{LabelSpam: 0, LabelHam: 0},
+			wantTokens: map[string]int{LabelSpam: 0, LabelHam: 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := trained(t, 1, nil)
+			if err := tt.steps(c); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.Save(); err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := Load(c.Path, c.MinDocs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			stats := loaded.Stats()
+			if got := stats["docs"]; !reflect.DeepEqual(got, tt.wantDocs) {
+				t.Errorf("docs = %v, want %v", got, tt.wantDocs)
+			}
+			if got := stats["tokens"]; !reflect.DeepEqual(got, tt.wantTokens) {
+				t.Errorf("tokens = %v, want %v", got, tt.wantTokens)
+			}
+		})
+	}
+}
+
+func TestTrainUnknownLabel(t *testing.T) {
+	c := trained(t, 1, nil)
+	for _, label := range []string{"", "Spam", "junk"} {
+		if err := c.Train("hello", label); err != ErrUnknownLabel {
+			t.Errorf("Train(label=%q) error = %v, want ErrUnknownLabel", label, err)
+		}
+	}
+}
+
+func TestStatsReturnsCopies(t *testing.T) {
+	c := trained(t, 1, map[string][]string{LabelSpam: {"buy now"}})
+	stats := c.Stats()
+	if err := c.Train("buy now", LabelSpam); err != nil {
+		t.Fatal(err)
+	}
+	if got := stats["docs"].(map[string]int)[LabelSpam]; got != 1 {
+		t.Errorf("earlier Stats docs changed to %d after training", got)
+	}
+}
+
+func TestLoadFillsMissingMaps(t *testing.T) {
+	for _, data := range []string{
+		`{}`,
+		`{"docs": null, "tokens": null, "counts": null}`,
+		`{"docs": {"spam": 1}, "tokens": {"spam": 2}, "counts": {"spam": {"buy": 2}}}`,
+		`{"counts": {"spam": null, "ham": null}}`,
+	} {
+		t.Run(data, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "model.json")
+			if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			c, err := Load(path, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, label := range []string{LabelSpam, LabelHam} {
+				if err := c.Train("buy cheap pills", label); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package discord
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"regexp"
+	"strings"
+)
+
+// MaxFiles is the number of attachments Discord accepts per message.
+const (
+	MaxFiles            = 10
+	maxPayloadJSONBytes = 128 << 10
+	sniffLen            = 512 // bytes http.DetectContentType looks at
+)
+
+var fileFieldPattern = regexp.MustCompile(`^(files\[\d+\]|file\d*)$`)
+
+// UploadLimits bounds webhook requests and the files they upload.
+type UploadLimits struct {
+	MaxBodyBytes int64 // whole request, JSON or multipart
+	MaxFileBytes int64
+	MaxFiles     int
+	AllowedTypes []string // sniffed MIME types, e.g. image/png; "image/*" matches a family
+}
+
+// CopyMultipart validates a multipart/form-data webhook request part by part
+// and re-encodes it into mw, closing mw on success. Files are streamed
+// through a per-part io.LimitReader and never held in memory; their content
+// type is sniffed rather than trusted. payload_json is passed through render,
+// validated like a JSON payload and written last, once the number of files is
+// known. Unknown form fields are dropped. Once a part is invalid nothing more
+// is written to mw, but the request is read to the end so that every problem
+// is reported as ValidationErrors; read and write errors (including an
+// exceeded body cap) are returned as is. files is the number of files written.
+func CopyMultipart(mw *multipart.Writer, mr *multipart.Reader, limits UploadLimits, render func([]byte) ([]byte, error)) (files int, err error) {
+	errs := ValidationErrors{}
+	var payload []byte
+	fileParts := 0 // including rejected ones, so a bad file isn't also reported as missing content
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		name := part.FormName()
+		switch {
+		case name == "payload_json":
+			data, tooLarge, err := readLimited(part, maxPayloadJSONBytes)
+			if err != nil {
+				return 0, err
+			}
+			if tooLarge {
+				errs.add("payload_json", "must be at most %d bytes", maxPayloadJSONBytes)
+				break
+			}
+			payload = data
+		case fileFieldPattern.MatchString(name):
+			field := fmt.Sprintf("files.%d", fileParts)
+			fileParts++
+			if fileParts > limits.MaxFiles {
+				if fileParts == limits.MaxFiles+1 {
+					errs.add("files", "must contain at most %d files", limits.MaxFiles)
+				}
+				if _, err := io.Copy(io.Discard, part); err != nil {
+					return 0, err
+				}
+				break
+			}
+			var dst *multipart.Writer
+			if len(errs) == 0 {
+				dst = mw
+			}
+			problem, err := copyFile(dst, files, part, limits)
+			if err != nil {
+				return 0, err
+			}
+			if problem != "" {
+				errs.add(field, "%s", problem)
+				break
+			}
+			files++
+		default:
+			if _, err := io.Copy(io.Discard, part); err != nil {
+				return 0, err
+			}
+		}
+		part.Close()
+	}
+
+	msg := &Message{AllowedMentions: NoMentions()}
+	if payload != nil {
+		rendered, err := render(payload)
+		if err != nil {
+			return 0, err
+		}
+		parsed, err := parsePayload(rendered, fileParts)
+		var vErrs ValidationErrors
+		if errors.As(err, &vErrs) {
+			for field, messages := range vErrs {
+				errs[field] = append(errs[field], messages...)
+			}
+		} else if err != nil {
+			return 0, err
+		}
+		msg = parsed
+	} else if fileParts == 0 {
+		errs.add("content", "content, embeds or files is required")
+	}
+
+	if len(errs) > 0 {
+		return 0, errs
+	}
+	encoded, err := json.Marshal(msg)
+	if err != nil {
+		return 0, err
+	}
+	if err := mw.WriteField("payload_json", string(encoded)); err != nil {
+		return 0, err
+	}
+	return files, mw.Close()
+}
+
+// copyFile streams one file part into mw as files[index], or only checks it
+// when mw is nil. Problems with the file itself are returned as a message;
+// err is only set when reading the request or writing mw failed. A file found
+// too large after it started streaming has been partly written to mw, which
+// the caller discards along with the validation error.
+func copyFile(mw *multipart.Writer, index int, part *multipart.Part, limits UploadLimits) (string, error) {
+	filename := strings.TrimSpace(part.FileName())
+	if filename == "" {
+		_, err := io.Copy(io.Discard, part)
+		return "must be a file upload with a filename", err
+	}
+
+	r := bufio.NewReaderSize(io.LimitReader(part, limits.MaxFileBytes+1), sniffLen)
+	head, err := r.Peek(sniffLen)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return "", err
+	}
+	if len(head) == 0 {
+		return "must not be empty", nil
+	}
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if !typeAllowed(contentType, limits.AllowedTypes) {
+		_, err := io.Copy(io.Discard, part)
+		return fmt.Sprintf("file type %s is not allowed", contentType), err
+	}
+
+	dst := io.Discard
+	if mw != nil {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+			"name":     fmt.Sprintf("files[%d]", index),
+			"filename": filename,
+		}))
+		header.Set("Content-Type", contentType)
+		if dst, err = mw.CreatePart(header); err != nil {
+			return "", err
+		}
+	}
+	n, err := io.Copy(dst, r)
+	if err != nil {
+		return "", err
+	}
+	if n > limits.MaxFileBytes {
+		_, err := io.Copy(io.Discard, part)
+		return fmt.Sprintf("must be at most %d bytes", limits.MaxFileBytes), err
+	}
+	return "", nil
+}
+
+// readLimited reads r fully unless it exceeds max bytes, in which case the
+// rest is discarded and tooLarge is set.
+func readLimited(r io.Reader, max int64) (data []byte, tooLarge bool, err error) {
+	data, err = io.ReadAll(io.LimitReader(r, max+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if int64(len(data)) > max {
+		if _, err := io.Copy(io.Discard, r); err != nil {
+			return nil, false, err
+		}
+		return nil, true, nil
+	}
+	return data, false, nil
+}
+
+func typeAllowed(contentType string, allowed []string) bool {
+	for _, a := range allowed {
+		if a == contentType || (strings.HasSuffix(a, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(a, "*"))) {
+			return true
+		}
+	}
+	return false
+}
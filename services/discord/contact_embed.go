@@ -0,0 +1,97 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	colorAccepted = 0x2e7d32
+	colorFlagged  = 0xf9a825
+	colorFailed   = 0xc62828
+	maxBodyRunes  = 1000
+)
+
+// ContactNotification is the data shown in the Discord embed for a contact message event.
+type ContactNotification struct {
+	Title        string // defaults to "New contact form message"
+	Name         string
+	From         string
+	Subject      string
+	Body         string
+	CaptchaScore float64
+	Country      string
+	SpamScore    float64
+	SpamDecision string
+	SubmissionID string
+	Error        string
+}
+
+// ContactMessage builds the webhook payload for a contact submission.
+// Mentions are always suppressed since every field is visitor-controlled.
+func ContactMessage(n ContactNotification) Message {
+	sender := n.From
+	if n.Name != "" {
+		sender = n.Name + " <" + n.From + ">"
+	}
+	country := n.Country
+	if country == "" {
+		country = "Unknown"
+	}
+
+	fields := []EmbedField{
+		{Name: "Sender", Value: truncate(sender, 256)},
+		{Name: "Subject", Value: truncate(orDash(n.Subject), 256)},
+		{Name: "Captcha score", Value: fmt.Sprintf("%.2f", n.CaptchaScore), Inline: true},
+		{Name: "Country", Value: country, Inline: true},
+	}
+	if n.SpamDecision != "" {
+		fields = append(fields, EmbedField{Name: "Spam", Value: fmt.Sprintf("%s (%.1f)", n.SpamDecision, n.SpamScore), Inline: true})
+	}
+	if n.Error != "" {
+		fields = append(fields, EmbedField{Name: "Error", Value: truncate(n.Error, 1024)})
+	}
+
+	color := colorAccepted
+	switch {
+	case n.Error != "" || n.SpamDecision == "reject":
+		color = colorFailed
+	case n.SpamDecision == "flag":
+		color = colorFlagged
+	}
+	title := n.Title
+	if title == "" {
+		title = "New contact form message"
+	}
+
+	embed := Embed{
+		Title:       title,
+		Description: truncate(orDash(n.Body), maxBodyRunes),
+		Color:       color,
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+		Fields:      fields,
+	}
+	if n.SubmissionID != "" {
+		embed.Footer = &EmbedFooter{Text: "Reference " + n.SubmissionID}
+	}
+
+	return Message{Embeds: []Embed{embed}, AllowedMentions: NoMentions()}
+}
+
+// truncate shortens s to at most max runes, marking the cut with an ellipsis.
+func truncate(s string, max int) string {
+	s = strings.TrimSpace(s)
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+func orDash(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "-"
+	}
+	return s
+}
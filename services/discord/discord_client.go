@@ -0,0 +1,158 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// StatusError is a non-2xx, non-429 response from Discord.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("discord webhook failed: status=%d response=%s", e.StatusCode, e.Body)
+}
+
+// Retryable reports whether the failure is worth retrying (server-side errors).
+func (e *StatusError) Retryable() bool {
+	return e.StatusCode >= 500
+}
+
+// ExecuteResult is the outcome of a successful webhook execution.
+type ExecuteResult struct {
+	StatusCode int
+	MessageID  string // only set when executed with wait=true
+	Body       []byte
+}
+
+// Client posts messages to Discord webhooks, honouring per-webhook rate-limit buckets.
+type Client struct {
+	WebhookURL string // default target for Send
+	HTTP       *http.Client
+	Buckets    *Buckets
+	// MaxInlineWait is the longest rate-limit delay Execute sleeps through
+	// before giving up with a RateLimitError.
+	MaxInlineWait time.Duration
+	MaxAttempts   int
+}
+
+func NewClient(webhookURL string) *Client {
+	return &Client{
+		WebhookURL:    webhookURL,
+		HTTP:          &http.Client{Timeout: 10 * time.Second},
+		Buckets:       defaultBuckets,
+		MaxInlineWait: 5 * time.Second,
+		MaxAttempts:   3,
+	}
+}
+
+// Send posts msg as JSON to the default webhook.
+func (c *Client) Send(msg Message) error {
+	if c.WebhookURL == "" {
+		return fmt.Errorf("discord webhook not configured")
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = c.Execute(c.WebhookURL, "application/json", body, false)
+	return err
+}
+
+// Execute posts body to webhookURL. Short rate-limit delays are waited out
+// inline; longer ones are returned as *RateLimitError. With wait=true Discord
+// returns the created message, whose ID is reported in the result. It blocks,
+// so request handlers use TryExecute or the queue instead.
+func (c *Client) Execute(webhookURL, contentType string, body []byte, wait bool) (*ExecuteResult, error) {
+	var lastErr error
+	for attempt := 0; attempt < max(c.MaxAttempts, 1); attempt++ {
+		if delay := c.Buckets.Delay(webhookURL); delay > 0 {
+			if delay > c.MaxInlineWait {
+				return nil, &RateLimitError{RetryAfter: delay}
+			}
+			time.Sleep(delay)
+		}
+
+		res, err := c.execute(webhookURL, contentType, bytes.NewReader(body), wait)
+		var rl *RateLimitError
+		if !errors.As(err, &rl) {
+			return res, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// TryExecute makes a single attempt without waiting. While the webhook's
+// bucket is exhausted it returns a *RateLimitError with the time until reset.
+func (c *Client) TryExecute(webhookURL, contentType string, body []byte, wait bool) (*ExecuteResult, error) {
+	return c.ExecuteStream(webhookURL, contentType, bytes.NewReader(body), wait)
+}
+
+// ExecuteStream is TryExecute for a body that can only be read once, such as
+// a pipe.
+func (c *Client) ExecuteStream(webhookURL, contentType string, body io.Reader, wait bool) (*ExecuteResult, error) {
+	if delay := c.Buckets.Delay(webhookURL); delay > 0 {
+		return nil, &RateLimitError{RetryAfter: delay}
+	}
+	return c.execute(webhookURL, contentType, body, wait)
+}
+
+func (c *Client) execute(webhookURL, contentType string, body io.Reader, wait bool) (*ExecuteResult, error) {
+	target := webhookURL
+	if wait {
+		target = withWait(webhookURL)
+	}
+
+	resp, err := c.HTTP.Post(target, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	c.Buckets.Update(webhookURL, resp.Header)
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		rl := parseRateLimit(resp.Header, respBody)
+		c.Buckets.Block(webhookURL, rl.RetryAfter, rl.Global)
+		return nil, rl
+	}
+	if resp.StatusCode >= 300 {
+		text := string(respBody)
+		if len(text) > 1000 {
+			text = text[:1000] + "...(truncated)"
+		}
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: text}
+	}
+
+	res := &ExecuteResult{StatusCode: resp.StatusCode, Body: respBody}
+	if wait {
+		var created struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(respBody, &created); err == nil {
+			res.MessageID = created.ID
+		}
+	}
+	return res, nil
+}
+
+func withWait(webhookURL string) string {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return webhookURL
+	}
+	q := u.Query()
+	q.Set("wait", "true")
+	u.RawQuery = q.Encode()
+	return u.String()
+}
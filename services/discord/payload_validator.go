@@ -0,0 +1,142 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Discord webhook limits (https://discord.com/developers/docs/resources/message#embed-object-embed-limits).
+const (
+	MaxContentLength    = 2000
+	MaxUsernameLength   = 80
+	MaxEmbeds           = 10
+	MaxEmbedTitle       = 256
+	MaxEmbedDescription = 4096
+	MaxEmbedFields      = 25
+	MaxFieldName        = 256
+	MaxFieldValue       = 1024
+	MaxFooterText       = 2048
+	MaxAuthorName       = 256
+	MaxThreadName       = 100
+	MaxEmbedTotalLength = 6000
+	suppressEmbedsFlag  = 1 << 2
+	suppressNotifyFlag  = 1 << 12
+	allowedMessageFlags = suppressEmbedsFlag | suppressNotifyFlag
+)
+
+// ValidationErrors maps a payload path (e.g. "embeds.0.fields.2.value") to its violations.
+type ValidationErrors map[string][]string
+
+func (v ValidationErrors) add(field, format string, args ...interface{}) {
+	v[field] = append(v[field], fmt.Sprintf(format, args...))
+}
+
+func (v ValidationErrors) Error() string {
+	fields := make([]string, 0, len(v))
+	for field := range v {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	parts := make([]string, 0, len(v))
+	for _, field := range fields {
+		parts = append(parts, field+": "+strings.Join(v[field], "; "))
+	}
+	return "invalid discord payload: " + strings.Join(parts, ", ")
+}
+
+// ParsePayload decodes a JSON webhook payload into a Message, dropping
+// unknown keys and forcing allowed_mentions to suppress all pings.
+// A ValidationErrors is returned when the message breaks Discord's limits.
+func ParsePayload(raw []byte) (*Message, error) {
+	return parsePayload(raw, 0)
+}
+
+func parsePayload(raw []byte, files int) (*Message, error) {
+	var msg Message
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return nil, fmt.Errorf("invalid JSON payload: %w", err)
+	}
+	msg.AllowedMentions = NoMentions()
+
+	if errs := validate(&msg, files); len(errs) > 0 {
+		return &msg, errs
+	}
+	return &msg, nil
+}
+
+// Validate checks msg against Discord's documented limits.
+func Validate(msg *Message) ValidationErrors {
+	return validate(msg, 0)
+}
+
+// validate checks msg for a request carrying the given number of files; a
+// message with files may omit content and embeds.
+func validate(msg *Message, files int) ValidationErrors {
+	errs := ValidationErrors{}
+
+	if strings.TrimSpace(msg.Content) == "" && len(msg.Embeds) == 0 && files == 0 {
+		errs.add("content", "content, embeds or files is required")
+	}
+	maxLen(errs, "content", msg.Content, MaxContentLength)
+	maxLen(errs, "username", msg.Username, MaxUsernameLength)
+	maxLen(errs, "thread_name", msg.ThreadName, MaxThreadName)
+	if msg.Flags&^allowedMessageFlags != 0 {
+		errs.add("flags", "only SUPPRESS_EMBEDS and SUPPRESS_NOTIFICATIONS flags are allowed")
+	}
+
+	if len(msg.Embeds) > MaxEmbeds {
+		errs.add("embeds", "must contain at most %d embeds, got %d", MaxEmbeds, len(msg.Embeds))
+	}
+
+	total := 0
+	for i, e := range msg.Embeds {
+		prefix := fmt.Sprintf("embeds.%d", i)
+		maxLen(errs, prefix+".title", e.Title, MaxEmbedTitle)
+		maxLen(errs, prefix+".description", e.Description, MaxEmbedDescription)
+		total += runes(e.Title) + runes(e.Description)
+
+		if len(e.Fields) > MaxEmbedFields {
+			errs.add(prefix+".fields", "must contain at most %d fields, got %d", MaxEmbedFields, len(e.Fields))
+		}
+		for j, f := range e.Fields {
+			fp := fmt.Sprintf("%s.fields.%d", prefix, j)
+			required(errs, fp+".name", f.Name)
+			required(errs, fp+".value", f.Value)
+			maxLen(errs, fp+".name", f.Name, MaxFieldName)
+			maxLen(errs, fp+".value", f.Value, MaxFieldValue)
+			total += runes(f.Name) + runes(f.Value)
+		}
+		if e.Footer != nil {
+			maxLen(errs, prefix+".footer.text", e.Footer.Text, MaxFooterText)
+			total += runes(e.Footer.Text)
+		}
+		if e.Author != nil {
+			maxLen(errs, prefix+".author.name", e.Author.Name, MaxAuthorName)
+			total += runes(e.Author.Name)
+		}
+	}
+	if total > MaxEmbedTotalLength {
+		errs.add("embeds", "combined embed text must be at most %d characters, got %d", MaxEmbedTotalLength, total)
+	}
+
+	return errs
+}
+
+func maxLen(errs ValidationErrors, field, value string, max int) {
+	if n := runes(value); n > max {
+		errs.add(field, "must be at most %d characters, got %d", max, n)
+	}
+}
+
+func required(errs ValidationErrors, field, value string) {
+	if strings.TrimSpace(value) == "" {
+		errs.add(field, "is required")
+	}
+}
+
+func runes(s string) int {
+	return utf8.RuneCountInString(s)
+}
@@ -0,0 +1,135 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitError is returned when Discord (or our local bucket state) says
+// the webhook may not be called again before RetryAfter.
+type RateLimitError struct {
+	RetryAfter time.Duration
+	Global     bool
+}
+
+func (e *RateLimitError) Error() string {
+	scope := "webhook"
+	if e.Global {
+		scope = "global"
+	}
+	return fmt.Sprintf("discord %s rate limit, retry after %s", scope, e.RetryAfter)
+}
+
+type bucketState struct {
+	remaining int
+	resetAt   time.Time
+}
+
+// Buckets tracks Discord rate-limit state per webhook, fed from the
+// X-RateLimit-* response headers and 429 bodies.
+type Buckets struct {
+	mu          sync.Mutex
+	buckets     map[string]*bucketState
+	globalUntil time.Time
+	now         func() time.Time
+}
+
+func NewBuckets() *Buckets {
+	return &Buckets{buckets: map[string]*bucketState{}, now: time.Now}
+}
+
+// defaultBuckets is shared by every Client so that the proxy and server-side
+// notifications posting to the same webhook respect one bucket.
+var defaultBuckets = NewBuckets()
+
+// Delay returns how long to wait before webhookURL may be called.
+func (b *Buckets) Delay(webhookURL string) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	delay := b.globalUntil.Sub(now)
+	if st, ok := b.buckets[bucketKey(webhookURL)]; ok && st.remaining <= 0 {
+		if d := st.resetAt.Sub(now); d > delay {
+			delay = d
+		}
+	}
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+// Update records the bucket headers of a Discord response.
+func (b *Buckets) Update(webhookURL string, h http.Header) {
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	resetAfter, err := strconv.ParseFloat(h.Get("X-RateLimit-Reset-After"), 64)
+	if err != nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buckets[bucketKey(webhookURL)] = &bucketState{
+		remaining: remaining,
+		resetAt:   b.now().Add(seconds(resetAfter)),
+	}
+}
+
+// Block marks the webhook (or every webhook, if global) as limited for retryAfter.
+func (b *Buckets) Block(webhookURL string, retryAfter time.Duration, global bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	until := b.now().Add(retryAfter)
+	if global {
+		b.globalUntil = until
+		return
+	}
+	b.buckets[bucketKey(webhookURL)] = &bucketState{remaining: 0, resetAt: until}
+}
+
+// parseRateLimit reads the retry delay from a 429 response body, falling back to headers.
+func parseRateLimit(h http.Header, body []byte) *RateLimitError {
+	var payload struct {
+		RetryAfter float64 `json:"retry_after"`
+		Global     bool    `json:"global"`
+	}
+	_ = json.Unmarshal(body, &payload)
+
+	rl := &RateLimitError{
+		RetryAfter: seconds(payload.RetryAfter),
+		Global:     payload.Global || h.Get("X-RateLimit-Global") == "true" || h.Get("X-RateLimit-Scope") == "global",
+	}
+	if rl.RetryAfter <= 0 {
+		if v, err := strconv.ParseFloat(h.Get("Retry-After"), 64); err == nil {
+			rl.RetryAfter = seconds(v)
+		} else if v, err := strconv.ParseFloat(h.Get("X-RateLimit-Reset-After"), 64); err == nil {
+			rl.RetryAfter = seconds(v)
+		} else {
+			rl.RetryAfter = time.Second
+		}
+	}
+	return rl
+}
+
+// bucketKey identifies a webhook by URL without query parameters.
+func bucketKey(webhookURL string) string {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return webhookURL
+	}
+	u.RawQuery = ""
+	return u.String()
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
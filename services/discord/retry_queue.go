@@ -0,0 +1,152 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"portfolio-backend/services/metrics"
+)
+
+var (
+	ErrQueueFull   = errors.New("discord queue is full")
+	ErrJobTooLarge = errors.New("discord message too large to queue")
+)
+
+// Job is a webhook execution waiting to be delivered.
+type Job struct {
+	Target      string // target name, for metrics
+	WebhookURL  string
+	ContentType string
+	Body        []byte
+	Attempts    int
+
+	// ctx carries the originating request's values for logging
+	ctx context.Context
+}
+
+// Queue delivers webhook executions in the background, retrying rate-limited
+// and server-side failures until MaxAttempts is reached. The worker never
+// waits: jobs for an exhausted bucket are rescheduled for its reset time.
+type Queue struct {
+	Client      *Client
+	MaxAttempts int
+	BaseBackoff time.Duration
+	// MaxBytes caps the bodies held by the queue, including jobs waiting for
+	// a retry; 0 means no cap.
+	MaxBytes int64
+	// MaxJobBytes caps a single job's body; 0 means no cap.
+	MaxJobBytes int64
+	jobs        chan *Job
+	bytes       atomic.Int64
+}
+
+func NewQueue(client *Client, size, maxAttempts int, maxBytes, maxJobBytes int64) *Queue {
+	return &Queue{
+		Client:      client,
+		MaxAttempts: maxAttempts,
+		BaseBackoff: 2 * time.Second,
+		MaxBytes:    maxBytes,
+		MaxJobBytes: maxJobBytes,
+		jobs:        make(chan *Job, size),
+	}
+}
+
+// Start launches the background worker.
+func (q *Queue) Start() {
+	go func() {
+		for job := range q.jobs {
+			q.process(job)
+		}
+	}()
+}
+
+// Enqueue schedules job for delivery without blocking. It returns
+// ErrJobTooLarge when the body exceeds MaxJobBytes, and ErrQueueFull when
+// the queue has no free slot or MaxBytes would be exceeded.
+func (q *Queue) Enqueue(ctx context.Context, job *Job) error {
+	size := int64(len(job.Body))
+	if q.MaxJobBytes > 0 && size > q.MaxJobBytes {
+		return ErrJobTooLarge
+	}
+	if n := q.bytes.Add(size); q.MaxBytes > 0 && n > q.MaxBytes {
+		q.bytes.Add(-size)
+		return ErrQueueFull
+	}
+	job.ctx = context.WithoutCancel(ctx)
+	if err := q.push(job); err != nil {
+		q.bytes.Add(-size)
+		return err
+	}
+	return nil
+}
+
+func (q *Queue) push(job *Job) error {
+	select {
+	case q.jobs <- job:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func (q *Queue) process(job *Job) {
+	ctx := job.ctx
+	// Waiting for the bucket to reset is not an attempt
+	if delay := q.Client.Buckets.Delay(job.WebhookURL); delay > 0 {
+		q.retryAfter(job, delay)
+		return
+	}
+
+	job.Attempts++
+	_, err := q.Client.TryExecute(job.WebhookURL, job.ContentType, job.Body, false)
+	if err == nil {
+		q.done(job, "delivered")
+		slog.InfoContext(ctx, "discord webhook delivered from queue", "attempts", job.Attempts)
+		return
+	}
+
+	var delay time.Duration
+	var rl *RateLimitError
+	var se *StatusError
+	switch {
+	case errors.As(err, &rl):
+		delay = max(rl.RetryAfter, q.Client.Buckets.Delay(job.WebhookURL))
+	case errors.As(err, &se) && !se.Retryable():
+		q.done(job, "dropped")
+		slog.WarnContext(ctx, "discord webhook dropped from queue", "error", err)
+		return
+	default:
+		// network errors and 5xx: exponential backoff
+		delay = q.BaseBackoff << (job.Attempts - 1)
+	}
+
+	if job.Attempts >= q.MaxAttempts {
+		q.done(job, "dropped")
+		slog.WarnContext(ctx, "discord webhook dropped", "attempts", job.Attempts, "error", err)
+		return
+	}
+
+	metrics.DiscordForwards.WithLabelValues(job.Target, "queue", "retried").Inc()
+	slog.InfoContext(ctx, "discord webhook retry scheduled", "attempt", job.Attempts, "delay", delay.String(), "error", err)
+	q.retryAfter(job, delay)
+}
+
+// retryAfter puts job back on the queue once delay has passed, keeping the
+// worker free for other webhooks meanwhile.
+func (q *Queue) retryAfter(job *Job, delay time.Duration) {
+	time.AfterFunc(delay, func() {
+		if err := q.push(job); err != nil {
+			q.done(job, "dropped")
+			slog.WarnContext(job.ctx, "discord webhook dropped on retry", "error", err)
+		}
+	})
+}
+
+// done releases the job's bytes and records its outcome.
+func (q *Queue) done(job *Job, outcome string) {
+	q.bytes.Add(-int64(len(job.Body)))
+	metrics.DiscordForwards.WithLabelValues(job.Target, "queue", outcome).Inc()
+}
@@ -0,0 +1,106 @@
+package discord
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/template"
+)
+
+// Target is a named webhook destination of the proxy. Each target only ever
+// posts to its own WebhookURL, so frontends sharing the backend can't cross-post.
+type Target struct {
+	Name       string
+	WebhookURL string
+	APIKeys    []string
+	RateLimit  int
+	Burst      int
+	Template   *template.Template // nil forwards the request payload as is
+}
+
+// Authorize reports whether key is one of the target's API keys. A target
+// without keys accepts every request.
+func (t *Target) Authorize(key string) bool {
+	if len(t.APIKeys) == 0 {
+		return true
+	}
+	ok := false
+	for _, k := range t.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+			ok = true
+		}
+	}
+	return ok
+}
+
+// Render runs the target template over the decoded request JSON and returns
+// the resulting Discord payload. Without a template raw is returned unchanged.
+func (t *Target) Render(raw []byte) ([]byte, error) {
+	if t.Template == nil {
+		return raw, nil
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("invalid JSON payload: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Template.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("render %s template: %w", t.Name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// LoadTemplate parses a payload template. Templates produce JSON, so the
+// "json" function encodes a value as a JSON literal and "truncate" shortens text:
+//
+//	{"content": {{ .message | truncate 2000 | json }}}
+func LoadTemplate(path string) (*template.Template, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return template.New(filepath.Base(path)).Option("missingkey=zero").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+		"truncate": func(max int, v interface{}) string {
+			if v == nil {
+				return ""
+			}
+			return truncate(fmt.Sprint(v), max)
+		},
+	}).Parse(string(src))
+}
+
+// Targets is the registry of named targets.
+type Targets struct {
+	byName map[string]*Target
+}
+
+func NewTargets(targets ...*Target) *Targets {
+	t := &Targets{byName: map[string]*Target{}}
+	for _, target := range targets {
+		t.byName[target.Name] = target
+	}
+	return t
+}
+
+// Get returns the named target, or nil if it isn't configured.
+func (t *Targets) Get(name string) *Target {
+	return t.byName[name]
+}
+
+// List returns the targets sorted by name.
+func (t *Targets) List() []*Target {
+	list := make([]*Target, 0, len(t.byName))
+	for _, target := range t.byName {
+		list = append(list, target)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
@@ -2,6 +2,7 @@ package email
 
 import (
 	"bytes"
+	"fmt"
 	"html/template"
 	"os"
 	"path/filepath"
@@ -25,6 +26,9 @@ type EmailData struct {
 	Header      template.HTML
 	Footer      template.HTML
 	Subcopy     template.HTML
+	Action      template.HTML
+	Form        template.HTML // rendered after the body, outside the markdown sanitizer
+	CSPNonce    string        // set when rendered as a web page under a nonce-based CSP
 }
 
 // ComponentData represents data for email components
@@ -102,6 +106,11 @@ func (es *MailRendererService) RenderFooter(content string) (template.HTML, erro
 
 // RenderContactEmail renders the contact form email
 func (es *MailRendererService) RenderContactEmail(data EmailData) (string, error) {
+	return es.renderLayout("components/ui/message.tmpl", data)
+}
+
+// renderLayout renders the content template into the shared header/footer layout
+func (es *MailRendererService) renderLayout(content string, data EmailData) (string, error) {
 	// Set default values if not provided
 	if data.Year == 0 {
 		data.Year = time.Now().Year()
@@ -130,8 +139,8 @@ func (es *MailRendererService) RenderContactEmail(data EmailData) (string, error
 	}
 	data.Footer = footer
 
-	// Render message content
-	slot, err := es.renderToString("components/ui/message.tmpl", data)
+	// Render content
+	slot, err := es.renderToString(content, data)
 	if err != nil {
 		return "", err
 	}
@@ -141,12 +150,80 @@ func (es *MailRendererService) RenderContactEmail(data EmailData) (string, error
 	return es.renderToString("components/ui/layout.tmpl", data)
 }
 
+// RenderConfirmationEmail renders the step-up confirmation email with a button to actionURL
+func (es *MailRendererService) RenderConfirmationEmail(data EmailData, actionURL string) (string, error) {
+	button, err := es.renderToString("components/ui/button.tmpl", map[string]interface{}{
+		"url":  actionURL,
+		"slot": "Confirm message",
+	})
+	if err != nil {
+		return "", err
+	}
+	data.Action = template.HTML(button)
+	return es.renderLayout("components/ui/confirmation.tmpl", data)
+}
+
+// RenderConfirmationPage renders the web page behind a confirmation link: a
+// button that POSTs fields to actionURL, so following the link alone sends nothing
+func (es *MailRendererService) RenderConfirmationPage(data EmailData, actionURL string, fields map[string]string) (string, error) {
+	form, err := es.renderToString("components/ui/form_button.tmpl", map[string]interface{}{
+		"url":    actionURL,
+		"fields": fields,
+		"slot":   "Send message",
+	})
+	if err != nil {
+		return "", err
+	}
+	data.Form = template.HTML(form)
+	return es.renderLayout("components/ui/confirm_page.tmpl", data)
+}
+
+// RenderConfirmedPage renders the page shown once a confirmed message was
+// delivered, with a link on to continueURL when it is set
+func (es *MailRendererService) RenderConfirmedPage(data EmailData, continueURL string) (string, error) {
+	if continueURL != "" {
+		button, err := es.renderToString("components/ui/button.tmpl", map[string]interface{}{
+			"url":  continueURL,
+			"slot": "Continue",
+		})
+		if err != nil {
+			return "", err
+		}
+		data.Form = template.HTML(button)
+	}
+	return es.renderLayout("components/ui/confirm_sent.tmpl", data)
+}
+
 func (es *MailRendererService) renderToString(name string, data interface{}) (string, error) {
 	var buf bytes.Buffer
 	err := es.templates.ExecuteTemplate(&buf, name, data)
 	return buf.String(), err
 }
 
+// requiredTemplates are the templates every outgoing email is built from
+var requiredTemplates = []string{
+	"components/ui/layout.tmpl",
+	"components/ui/header.tmpl",
+	"components/ui/footer.tmpl",
+	"components/ui/message.tmpl",
+	"components/ui/sender.tmpl",
+	"components/ui/confirmation.tmpl",
+	"components/ui/button.tmpl",
+	"components/ui/confirm_page.tmpl",
+	"components/ui/form_button.tmpl",
+	"components/ui/confirm_sent.tmpl",
+}
+
+// CheckTemplates reports the first required template that failed to load
+func (es *MailRendererService) CheckTemplates() error {
+	for _, name := range requiredTemplates {
+		if es.templates.Lookup(name) == nil {
+			return fmt.Errorf("email template %s not loaded", name)
+		}
+	}
+	return nil
+}
+
 func (es *MailRendererService) Templates() *template.Template {
 	return es.templates
 }
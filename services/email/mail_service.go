@@ -1,23 +1,33 @@
 package email
 
 import (
-	"log"
-	"os"
-	validation_email "portfolio-backend/services/validation/email"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"portfolio-backend/config"
+	"portfolio-backend/services/metrics"
+	"portfolio-backend/services/tracing"
 	"portfolio-backend/utils"
 	"strconv"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"gopkg.in/gomail.v2"
 )
 
 type MailService struct {
 	MailRendererService *MailRendererService
+	Config              *config.MailConfig
+	AppName             string
+	AppURL              string
 }
 
-func NewMailService(mailRendererService *MailRendererService) *MailService {
-	return &MailService{MailRendererService: mailRendererService}
+func NewMailService(mailRendererService *MailRendererService, cfg *config.MailConfig, appName, appURL string) *MailService {
+	return &MailService{MailRendererService: mailRendererService, Config: cfg, AppName: appName, AppURL: appURL}
 }
 
 type ContactRequest struct {
@@ -25,32 +35,31 @@ type ContactRequest struct {
 	Subject string
 	Body    string
 	Name    string
-}
 
-func (cs *MailService) SendContactEmail(req ContactRequest) error {
-	// Validate email
-	_, err := validation_email.ValidateEmail(req.From)
-	if err != nil {
-		return err
-	}
+	SubmissionID string // reference used to label the message spam/ham
+}
 
+// SendContactEmail mails a contact submission to the site owner. The caller
+// validates req.From beforehand.
+func (cs *MailService) SendContactEmail(ctx context.Context, req ContactRequest) error {
 	// Render sender info
 	var senderInfo strings.Builder
-	err = cs.MailRendererService.Templates().ExecuteTemplate(&senderInfo, "components/ui/sender.tmpl", map[string]interface{}{
-		"FromEmail": req.From,
-		"Subject":   req.Subject,
+	err := cs.MailRendererService.Templates().ExecuteTemplate(&senderInfo, "components/ui/sender.tmpl", map[string]interface{}{
+		"FromEmail":    req.From,
+		"Subject":      req.Subject,
+		"SubmissionID": req.SubmissionID,
 	})
 	if err != nil {
-		log.Printf("Failed to render sender info: %v", err)
+		slog.ErrorContext(ctx, "failed to render sender info", "error", err)
 	}
 
 	emailBodyWithSenderInfo := senderInfo.String() + req.Body
 
 	emailData := EmailData{
-		AppName:     utils.GetEnvOrDefault("APP_NAME", "Portfolio"),
+		AppName:     cs.AppName,
 		Subject:     req.Subject,
-		SiteURL:     utils.GetEnvOrDefault("APP_URL", "https://yourdomain.com"),
-		HeaderTitle: utils.GetEnvOrDefault("APP_NAME", "Portfolio"),
+		SiteURL:     cs.AppURL,
+		HeaderTitle: cs.AppName,
 		Year:        time.Now().Year(),
 		FromName:    utils.GetNameFromEmail(req.From, req.Name),
 		FromEmail:   req.From,
@@ -59,48 +68,139 @@ func (cs *MailService) SendContactEmail(req ContactRequest) error {
 
 	htmlBody, err := cs.MailRendererService.RenderContactEmail(emailData)
 	if err != nil {
-		log.Printf("Failed to render email template: %v", err)
+		slog.ErrorContext(ctx, "failed to render email template", "error", err)
 		htmlBody = generateFallbackHTML(req)
 	}
 
+	return cs.send(ctx, cs.Config.ContactEmail, req.Subject, htmlBody)
+}
+
+// SendConfirmationEmail asks the sender of a held submission to confirm it via confirmURL
+func (cs *MailService) SendConfirmationEmail(ctx context.Context, to, name, confirmURL string) error {
+	emailData := EmailData{
+		AppName:     cs.AppName,
+		Subject:     "Confirm your message",
+		SiteURL:     cs.AppURL,
+		HeaderTitle: cs.AppName,
+		Year:        time.Now().Year(),
+		FromName:    name,
+		FromEmail:   to,
+	}
+
+	htmlBody, err := cs.MailRendererService.RenderConfirmationEmail(emailData, confirmURL)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to render confirmation email", "error", err)
+		return err
+	}
+
+	return cs.send(ctx, to, "Confirm your message to "+cs.AppName, htmlBody)
+}
+
+// GenerateConfirmationPage renders the page a confirmation link opens, whose
+// button POSTs the challenge id and token back to actionURL
+func (cs *MailService) GenerateConfirmationPage(cspNonce, actionURL, id, token string) (string, error) {
+	emailData := EmailData{
+		AppName:     cs.AppName,
+		Subject:     "Confirm your message",
+		SiteURL:     cs.AppURL,
+		HeaderTitle: cs.AppName,
+		Year:        time.Now().Year(),
+		CSPNonce:    cspNonce,
+	}
+
+	return cs.MailRendererService.RenderConfirmationPage(emailData, actionURL, map[string]string{
+		"id":    id,
+		"token": token,
+	})
+}
+
+// GenerateConfirmedPage renders the page shown after a confirmed message was
+// delivered, linking on to continueURL when it is set
+func (cs *MailService) GenerateConfirmedPage(cspNonce, continueURL string) (string, error) {
+	emailData := EmailData{
+		AppName:     cs.AppName,
+		Subject:     "Message sent",
+		SiteURL:     cs.AppURL,
+		HeaderTitle: cs.AppName,
+		Year:        time.Now().Year(),
+		CSPNonce:    cspNonce,
+	}
+
+	return cs.MailRendererService.RenderConfirmedPage(emailData, continueURL)
+}
+
+func (cs *MailService) send(ctx context.Context, to, subject, htmlBody string) error {
 	m := gomail.NewMessage()
-	m.SetHeader("From", os.Getenv("EMAIL_FROM"))
-	m.SetHeader("To", os.Getenv("THIS_PORTFOLIO_CONTACT_EMAIL"))
-	m.SetHeader("Subject", req.Subject)
+	m.SetHeader("From", cs.Config.From)
+	m.SetHeader("To", to)
+	m.SetHeader("Subject", subject)
 	m.SetBody("text/html", htmlBody)
 	plainBody := utils.StripHTMLTags(htmlBody)
 	m.AddAlternative("text/plain", plainBody)
 
-	port, err := strconv.Atoi(os.Getenv("SMTP_PORT"))
+	d := gomail.NewDialer(
+		cs.Config.Host,
+		cs.Config.Port,
+		cs.Config.User,
+		cs.Config.Password,
+	)
+
+	_, span := tracing.Start(ctx, "smtp.send", attribute.String("smtp.host", d.Host))
+	start := time.Now()
+	err := d.DialAndSend(m)
+	metrics.SMTPSendDuration.Observe(time.Since(start).Seconds())
+	tracing.End(span, err)
 	if err != nil {
+		metrics.SMTPSendFailures.Inc()
+		slog.ErrorContext(ctx, "failed to send email", "error", err)
 		return err
 	}
 
-	d := gomail.NewDialer(
-		os.Getenv("SMTP_HOST"),
-		port,
-		os.Getenv("SMTP_USER"),
-		os.Getenv("SMTP_PASS"),
-	)
+	return nil
+}
 
-	if err := d.DialAndSend(m); err != nil {
-		log.Printf("Failed to send email: %v", err)
+// PingSMTP connects to the SMTP server and waits for its greeting without
+// authenticating or sending anything. Port 465 uses implicit TLS.
+func (cs *MailService) PingSMTP(ctx context.Context) error {
+	host := cs.Config.Host
+	if host == "" {
+		return fmt.Errorf("SMTP_HOST not set")
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(cs.Config.Port))
+
+	var conn net.Conn
+	var err error
+	if cs.Config.Port == 465 {
+		conn, err = (&tls.Dialer{Config: &tls.Config{ServerName: host}}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
 		return err
 	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
 
-	return nil
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	return c.Quit()
 }
 
-func (cs *MailService) GeneratePreviewEmail() (string, error) {
+func (cs *MailService) GeneratePreviewEmail(cspNonce string) (string, error) {
 	emailData := EmailData{
-		AppName:     utils.GetEnvOrDefault("APP_NAME", "Ivan Bandilla Portfolio"),
+		AppName:     cs.AppName,
 		Subject:     "Contact Form Preview",
-		SiteURL:     utils.GetEnvOrDefault("APP_URL", "https://ivanbandilla.dev"),
-		HeaderTitle: utils.GetEnvOrDefault("APP_NAME", "Ivan Bandilla"),
+		SiteURL:     cs.AppURL,
+		HeaderTitle: cs.AppName,
 		Year:        time.Now().Year(),
 		FromName:    "John Doe",
 		FromEmail:   "john.doe@example.com",
 		Body:        "This is a preview of your Laravel-style email template. The styling matches Laravel's default mail templates exactly!",
+		CSPNonce:    cspNonce,
 	}
 
 	htmlBody, err := cs.MailRendererService.RenderContactEmail(emailData)
@@ -0,0 +1,78 @@
+package geoip
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Resolver determines a visitor's country, preferring the header set by the
+// CDN/proxy in front of the app and falling back to an optional HTTP lookup
+// service.
+type Resolver struct {
+	// CountryHeader is set by the proxy in front of the app (e.g. CF-IPCountry
+	// behind Cloudflare), which overwrites any value sent by the client.
+	// Empty ignores country headers, since clients can send any of them.
+	CountryHeader string
+	// LookupURL is queried with {ip} replaced and must answer with a plain
+	// two-letter country code (e.g. https://ipapi.co/{ip}/country/). Empty disables lookups.
+	LookupURL string
+	Client    *http.Client
+}
+
+func NewResolver(countryHeader, lookupURL string) *Resolver {
+	return &Resolver{CountryHeader: countryHeader, LookupURL: lookupURL, Client: &http.Client{Timeout: 2 * time.Second}}
+}
+
+// Country returns the ISO country code for the request, or "" when unknown.
+// r may be nil when only the IP is available.
+func (res *Resolver) Country(r *http.Request, ip string) string {
+	if r != nil {
+		if c := res.FromHeaders(r); c != "" {
+			return c
+		}
+	}
+	return res.Lookup(ip)
+}
+
+// FromHeaders returns the country reported in CountryHeader, or "".
+func (res *Resolver) FromHeaders(r *http.Request) string {
+	if res == nil || res.CountryHeader == "" {
+		return ""
+	}
+	return normalize(r.Header.Get(res.CountryHeader))
+}
+
+// Lookup queries LookupURL for ip. Anything that is not an IP address is
+// ignored, so a spoofed client IP cannot change the request path or host.
+func (res *Resolver) Lookup(ip string) string {
+	if res.LookupURL == "" {
+		return ""
+	}
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+	if parsed == nil {
+		return ""
+	}
+	resp, err := res.Client.Get(strings.ReplaceAll(res.LookupURL, "{ip}", url.PathEscape(parsed.String())))
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ""
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 16))
+	return normalize(string(body))
+}
+
+func normalize(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	// "XX" and "T1" are Cloudflare's unknown/Tor markers
+	if len(code) != 2 || code == "XX" || code == "T1" {
+		return ""
+	}
+	return code
+}
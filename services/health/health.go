@@ -0,0 +1,149 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Probe checks one dependency, returning an error when it is unusable.
+type Probe func(ctx context.Context) error
+
+// DependencyStatus is what we last learned about a dependency.
+type DependencyStatus struct {
+	Name          string     `json:"name"`
+	Healthy       bool       `json:"healthy"`
+	LatencyMS     int64      `json:"latency_ms"`
+	LastCheckedAt *time.Time `json:"last_checked_at,omitempty"`
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt *time.Time `json:"last_failure_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+}
+
+// Checker runs the registered probes and remembers their outcomes. Results
+// are reused for CacheTTL so frequent readiness checks don't hammer
+// dependencies such as the SMTP server.
+type Checker struct {
+	Timeout  time.Duration // per probe
+	CacheTTL time.Duration
+
+	mu       sync.Mutex
+	names    []string
+	probes   map[string]Probe
+	statuses map[string]*DependencyStatus
+	lastRun  time.Time
+	running  chan struct{} // closed when the probe round in flight ends; nil when idle
+	now      func() time.Time
+}
+
+func NewChecker(timeout, cacheTTL time.Duration) *Checker {
+	return &Checker{
+		Timeout:  timeout,
+		CacheTTL: cacheTTL,
+		probes:   map[string]Probe{},
+		statuses: map[string]*DependencyStatus{},
+		now:      time.Now,
+	}
+}
+
+// Register adds a named probe. Probes run in registration order in reports.
+func (c *Checker) Register(name string, probe Probe) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.probes[name]; !ok {
+		c.names = append(c.names, name)
+	}
+	c.probes[name] = probe
+	c.statuses[name] = &DependencyStatus{Name: name}
+}
+
+// Check runs every probe concurrently unless the cached results are still
+// fresh, and reports whether all dependencies are healthy. Concurrent checks
+// share one probe round, which runs detached from the caller's context so a
+// client hanging up is never recorded as a failed dependency; a caller that
+// gives up early gets the previous results.
+func (c *Checker) Check(ctx context.Context) (bool, []DependencyStatus) {
+	c.mu.Lock()
+	fresh := !c.lastRun.IsZero() && c.now().Sub(c.lastRun) < c.CacheTTL
+	running := c.running
+	if !fresh && running == nil {
+		running = make(chan struct{})
+		c.running = running
+		go func() {
+			c.run(context.WithoutCancel(ctx))
+			c.mu.Lock()
+			c.running = nil
+			c.mu.Unlock()
+			close(running)
+		}()
+	}
+	c.mu.Unlock()
+
+	if !fresh {
+		select {
+		case <-running:
+		case <-ctx.Done():
+		}
+	}
+	return c.report()
+}
+
+func (c *Checker) run(ctx context.Context) {
+	c.mu.Lock()
+	names := append([]string(nil), c.names...)
+	c.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			c.probe(ctx, name)
+		}(name)
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	c.lastRun = c.now()
+	c.mu.Unlock()
+}
+
+func (c *Checker) probe(ctx context.Context, name string) {
+	c.mu.Lock()
+	probe := c.probes[name]
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+	start := c.now()
+	err := probe(ctx)
+	end := c.now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	st := c.statuses[name]
+	st.Healthy = err == nil
+	st.LatencyMS = end.Sub(start).Milliseconds()
+	st.LastCheckedAt = &end
+	if err != nil {
+		st.LastFailureAt = &end
+		st.LastError = err.Error()
+	} else {
+		st.LastSuccessAt = &end
+	}
+}
+
+func (c *Checker) report() (bool, []DependencyStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	healthy := true
+	out := make([]DependencyStatus, 0, len(c.names))
+	for _, name := range c.names {
+		st := *c.statuses[name]
+		if !st.Healthy {
+			healthy = false
+		}
+		out = append(out, st)
+	}
+	return healthy, out
+}
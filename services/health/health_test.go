@@ -0,0 +1,61 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckSharesOneProbeRound(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	c := NewChecker(time.Second, time.Minute)
+	c.Register("slow", func(ctx context.Context) error {
+		calls.Add(1)
+		<-release
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if healthy, _ := c.Check(context.Background()); !healthy {
+				t.Error("Check healthy = false")
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("probe ran %d times, want 1", n)
+	}
+}
+
+func TestCheckIgnoresCallerCancellation(t *testing.T) {
+	c := NewChecker(time.Second, time.Minute)
+	c.Register("slow", func(ctx context.Context) error {
+		select {
+		case <-time.After(50 * time.Millisecond):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.Check(ctx)
+
+	// The round started by the cancelled caller still completes and is cached
+	time.Sleep(100 * time.Millisecond)
+	healthy, statuses := c.Check(context.Background())
+	if !healthy || statuses[0].LastError != "" {
+		t.Errorf("Check = %v %+v, want healthy", healthy, statuses)
+	}
+}
@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ctxKey struct{}
+
+// RequestInfo identifies the request a log line belongs to.
+type RequestInfo struct {
+	RequestID string
+	ClientIP  string
+	Route     string
+}
+
+// WithRequest returns a context whose log lines carry info.
+func WithRequest(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, ctxKey{}, info)
+}
+
+// RequestFromContext returns the request info stored by WithRequest.
+func RequestFromContext(ctx context.Context) (RequestInfo, bool) {
+	if ctx == nil {
+		return RequestInfo{}, false
+	}
+	info, ok := ctx.Value(ctxKey{}).(RequestInfo)
+	return info, ok
+}
+
+// ContextHandler adds the request ID, client IP, route and trace IDs from the
+// context to every record, so services only need to log with slog.*Context.
+type ContextHandler struct {
+	slog.Handler
+}
+
+func (h ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if info, ok := RequestFromContext(ctx); ok {
+		r.AddAttrs(slog.String("request_id", info.RequestID), slog.String("client_ip", info.ClientIP))
+		if info.Route != "" {
+			r.AddAttrs(slog.String("route", info.Route))
+		}
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return ContextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h ContextHandler) WithGroup(name string) slog.Handler {
+	return ContextHandler{h.Handler.WithGroup(name)}
+}
+
+// New builds a logger writing to w at level, as JSON or as text.
+func New(w io.Writer, level slog.Level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	if strings.ToLower(format) == "text" {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+	return slog.New(ContextHandler{handler})
+}
+
+// ParseLevel maps debug, info, warn and error to a slog level, defaulting to info.
+func ParseLevel(level string) slog.Level {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.TrimSpace(level))); err != nil {
+		return slog.LevelInfo
+	}
+	return l
+}
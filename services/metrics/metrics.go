@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every collector served on /metrics. A dedicated registry
+// keeps third-party packages from adding metrics we did not ask for.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests counts responses by matched route pattern, method and status code.
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP responses by route, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	// HTTPRequestDuration observes request latency by route and method.
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by route and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	// RateLimitDecisions counts per-route rate limiter outcomes (allowed, denied, error).
+	RateLimitDecisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "rate_limit_decisions_total",
+		Help: "Rate limiter decisions by base key and outcome.",
+	}, []string{"key", "decision"})
+
+	// CaptchaScores observes the scores of successfully verified captcha tokens.
+	CaptchaScores = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "captcha_score",
+		Help:    "Captcha scores of verified tokens by provider and action.",
+		Buckets: prometheus.LinearBuckets(0.1, 0.1, 10),
+	}, []string{"provider", "action"})
+
+	// EmailValidations counts sender address checks by provider (format, primary,
+	// secondary) and outcome (valid, invalid, error).
+	EmailValidations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "email_validations_total",
+		Help: "Email validation results by provider and outcome.",
+	}, []string{"provider", "outcome"})
+
+	// SMTPSendDuration observes how long SMTP deliveries take, failed or not.
+	SMTPSendDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "smtp_send_duration_seconds",
+		Help:    "Time spent dialing the SMTP server and sending a message.",
+		Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	})
+
+	// SMTPSendFailures counts SMTP deliveries that returned an error.
+	SMTPSendFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "smtp_send_failures_total",
+		Help: "SMTP deliveries that failed.",
+	})
+
+	// DiscordForwards counts Discord proxy results by target, mode (sync or
+	// queue) and result.
+	DiscordForwards = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "discord_forwards_total",
+		Help: "Discord webhook forwards by target, mode and result.",
+	}, []string{"target", "mode", "result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		RateLimitDecisions,
+		CaptchaScores,
+		EmailValidations,
+		SMTPSendDuration,
+		SMTPSendFailures,
+		DiscordForwards,
+	)
+}
+
+// Handler serves the registry in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
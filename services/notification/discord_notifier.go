@@ -0,0 +1,34 @@
+package notification
+
+import (
+	"context"
+
+	"portfolio-backend/services/discord"
+)
+
+// DiscordNotifier posts events as embeds through a Discord webhook.
+type DiscordNotifier struct {
+	Client *discord.Client
+}
+
+func NewDiscordNotifier(webhookURL string) *DiscordNotifier {
+	return &DiscordNotifier{Client: discord.NewClient(webhookURL)}
+}
+
+func (n *DiscordNotifier) Name() string { return "discord" }
+
+func (n *DiscordNotifier) Notify(ctx context.Context, e Event) error {
+	return n.Client.Send(discord.ContactMessage(discord.ContactNotification{
+		Title:        e.Title(),
+		Name:         e.Contact.Name,
+		From:         e.Contact.From,
+		Subject:      e.Contact.Subject,
+		Body:         e.Contact.Body,
+		CaptchaScore: e.CaptchaScore,
+		Country:      e.Country,
+		SpamScore:    e.SpamScore,
+		SpamDecision: e.SpamDecision,
+		SubmissionID: e.Contact.SubmissionID,
+		Error:        e.Error,
+	}))
+}
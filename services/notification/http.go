@@ -0,0 +1,81 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type summaryField struct {
+	name  string
+	value string
+}
+
+// summaryFields are the key/value lines text-based channels show for an event.
+func summaryFields(e Event) []summaryField {
+	sender := e.Contact.From
+	if e.Contact.Name != "" {
+		sender = e.Contact.Name + " <" + e.Contact.From + ">"
+	}
+	fields := []summaryField{
+		{"Sender", sender},
+		{"Subject", e.Contact.Subject},
+		{"Captcha score", fmt.Sprintf("%.2f", e.CaptchaScore)},
+	}
+	if e.Country != "" {
+		fields = append(fields, summaryField{"Country", e.Country})
+	}
+	if e.SpamDecision != "" {
+		fields = append(fields, summaryField{"Spam", fmt.Sprintf("%s (%.1f)", e.SpamDecision, e.SpamScore)})
+	}
+	if e.Error != "" {
+		fields = append(fields, summaryField{"Error", e.Error})
+	}
+	if e.Contact.SubmissionID != "" {
+		fields = append(fields, summaryField{"Reference", e.Contact.SubmissionID})
+	}
+	return fields
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}, headers map[string]string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return postRaw(ctx, client, url, body, headers)
+}
+
+func postRaw(ctx context.Context, client *http.Client, url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 500))
+		return fmt.Errorf("status=%d response=%s", resp.StatusCode, respBody)
+	}
+	return nil
+}
+
+func truncate(s string, max int) string {
+	s = strings.TrimSpace(s)
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,141 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"portfolio-backend/config"
+)
+
+// Event types that can be routed to channels; the list lives in config,
+// which reads a route setting for each.
+const (
+	EventContactReceived       = config.EventContactReceived
+	EventContactDeliveryFailed = config.EventContactDeliveryFailed
+	EventContactSpamBlocked    = config.EventContactSpamBlocked
+)
+
+// EventTypes lists every event type, in the order used for default routing.
+var EventTypes = config.EventTypes
+
+// Contact is the submission an event is about.
+type Contact struct {
+	Name         string `json:"name,omitempty"`
+	From         string `json:"from"`
+	Subject      string `json:"subject"`
+	Body         string `json:"body"`
+	SubmissionID string `json:"submission_id,omitempty"`
+}
+
+// Event is a notification-worthy occurrence in the contact pipeline.
+type Event struct {
+	Type         string    `json:"type"`
+	Contact      Contact   `json:"contact"`
+	CaptchaScore float64   `json:"captcha_score"`
+	Country      string    `json:"country,omitempty"`
+	SpamScore    float64   `json:"spam_score"`
+	SpamDecision string    `json:"spam_decision,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
+
+// Title is a short human-readable summary of the event type.
+func (e Event) Title() string {
+	switch e.Type {
+	case EventContactReceived:
+		return "New contact form message"
+	case EventContactDeliveryFailed:
+		return "Contact message delivery failed"
+	case EventContactSpamBlocked:
+		return "Contact message blocked as spam"
+	default:
+		return e.Type
+	}
+}
+
+// Notifier delivers events to one channel.
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, e Event) error
+}
+
+// Dispatcher fans events out to the channels routed for their type.
+// Each channel runs independently so one failing channel never affects the others.
+type Dispatcher struct {
+	channels map[string]Notifier
+	routes   map[string][]string
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{channels: map[string]Notifier{}, routes: map[string][]string{}}
+}
+
+// Register adds a channel under its Name.
+func (d *Dispatcher) Register(n Notifier) {
+	d.channels[n.Name()] = n
+}
+
+// Route sends events of eventType to the named channels. Channels that are
+// not registered (usually because they are not configured) are left out and
+// returned so the caller can report them.
+func (d *Dispatcher) Route(eventType string, channels ...string) (skipped []string) {
+	var routed []string
+	for _, c := range channels {
+		if _, ok := d.channels[c]; !ok {
+			skipped = append(skipped, c)
+			continue
+		}
+		routed = append(routed, c)
+	}
+	d.routes[eventType] = routed
+	return skipped
+}
+
+// Channels returns the names of the registered channels.
+func (d *Dispatcher) Channels() []string {
+	names := make([]string, 0, len(d.channels))
+	for name := range d.channels {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Dispatch notifies every channel routed for e.Type in parallel and
+// returns the failures by channel name.
+func (d *Dispatcher) Dispatch(ctx context.Context, e Event) map[string]error {
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now().UTC()
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	failures := map[string]error{}
+	for _, name := range d.routes[e.Type] {
+		n := d.channels[name]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := notifySafely(ctx, n, e); err != nil {
+				slog.ErrorContext(ctx, "notification failed", "channel", name, "event", e.Type, "error", err)
+				mu.Lock()
+				failures[name] = err
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return failures
+}
+
+// notifySafely turns a panicking driver into an error.
+func notifySafely(ctx context.Context, n Notifier, e Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return n.Notify(ctx, e)
+}
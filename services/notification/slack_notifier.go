@@ -0,0 +1,39 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SlackNotifier posts events to a Slack incoming webhook.
+type SlackNotifier struct {
+	WebhookURL string
+	HTTP       *http.Client
+}
+
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{WebhookURL: webhookURL, HTTP: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (n *SlackNotifier) Name() string { return "slack" }
+
+func (n *SlackNotifier) Notify(ctx context.Context, e Event) error {
+	lines := []string{"*" + slackEscape(e.Title()) + "*"}
+	for _, f := range summaryFields(e) {
+		lines = append(lines, fmt.Sprintf("*%s:* %s", f.name, slackEscape(f.value)))
+	}
+	if body := truncate(e.Contact.Body, 1500); body != "" {
+		lines = append(lines, ">"+strings.ReplaceAll(slackEscape(body), "\n", "\n>"))
+	}
+	return postJSON(ctx, n.HTTP, n.WebhookURL, map[string]interface{}{
+		"text": strings.Join(lines, "\n"),
+	}, nil)
+}
+
+// slackEscape escapes the control characters of Slack's mrkdwn format.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,47 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TelegramNotifier sends events as messages from a Telegram bot to a chat.
+type TelegramNotifier struct {
+	BotToken string
+	ChatID   string
+	APIURL   string
+	HTTP     *http.Client
+}
+
+func NewTelegramNotifier(botToken, chatID string) *TelegramNotifier {
+	return &TelegramNotifier{
+		BotToken: botToken,
+		ChatID:   chatID,
+		APIURL:   "https://api.telegram.org",
+		HTTP:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (n *TelegramNotifier) Name() string { return "telegram" }
+
+func (n *TelegramNotifier) Notify(ctx context.Context, e Event) error {
+	lines := []string{"<b>" + html.EscapeString(e.Title()) + "</b>"}
+	for _, f := range summaryFields(e) {
+		lines = append(lines, fmt.Sprintf("<b>%s:</b> %s", f.name, html.EscapeString(f.value)))
+	}
+	if body := truncate(e.Contact.Body, 3000); body != "" {
+		lines = append(lines, "", html.EscapeString(body))
+	}
+
+	endpoint := fmt.Sprintf("%s/bot%s/sendMessage", strings.TrimRight(n.APIURL, "/"), n.BotToken)
+	return postJSON(ctx, n.HTTP, endpoint, map[string]interface{}{
+		"chat_id":                  n.ChatID,
+		"text":                     strings.Join(lines, "\n"),
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+	}, nil)
+}
@@ -0,0 +1,50 @@
+package notification
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// WebhookNotifier posts the raw event as JSON to any URL, signed with HMAC-SHA256
+// over "<timestamp>.<body>" so receivers can verify origin and freshness.
+type WebhookNotifier struct {
+	URL    string
+	Secret string
+	HTTP   *http.Client
+}
+
+func NewWebhookNotifier(url, secret string) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, Secret: secret, HTTP: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (n *WebhookNotifier) Name() string { return "webhook" }
+
+func (n *WebhookNotifier) Notify(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	headers := map[string]string{
+		"X-Event-Type":      e.Type,
+		"X-Event-Timestamp": ts,
+	}
+	if n.Secret != "" {
+		headers["X-Event-Signature"] = "sha256=" + Sign(n.Secret, ts, body)
+	}
+	return postRaw(ctx, n.HTTP, n.URL, body, headers)
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>".
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
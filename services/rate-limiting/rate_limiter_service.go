@@ -13,8 +13,7 @@ type RedisRateLimiter struct {
 	Client *redis.Client
 }
 
-func (r *RedisRateLimiter) Allow(key string, limit int, ttl time.Duration) (bool, int, error) {
-	ctx := context.Background()
+func (r *RedisRateLimiter) Allow(ctx context.Context, key string, limit int, ttl time.Duration) (bool, int, error) {
 	count, err := r.Client.Incr(ctx, key).Result()
 	if err != nil {
 		return false, int(ttl.Seconds()), err
@@ -4,10 +4,11 @@ import (
 	"context"
 	"time"
 
-	"portfolio-backend/app/middlewares"
 	"portfolio-backend/config"
+	"portfolio-backend/services/tracing"
 
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type UpstashService struct {
@@ -20,20 +21,80 @@ func NewUpstashService() *UpstashService {
 }
 
 // Allow implements the RateLimiterService interface
-func (u *UpstashService) Allow(key string, limit int, ttl time.Duration) (bool, int, error) {
-	ctx := context.Background()
+func (u *UpstashService) Allow(ctx context.Context, key string, limit int, ttl time.Duration) (bool, int, error) {
+	ctx, span := tracing.Start(ctx, "redis.RateLimit", attribute.String("db.system", "redis"), attribute.Int("ratelimit.limit", limit))
 	count, err := u.Client.Incr(ctx, key).Result()
 	if err != nil {
+		tracing.End(span, err)
 		return false, int(ttl.Seconds()), err
 	}
 	if count == 1 {
 		u.Client.Expire(ctx, key, ttl)
 	}
-	if int(count) > limit {
+	allowed := int(count) <= limit
+	span.SetAttributes(attribute.Int64("ratelimit.count", count), attribute.Bool("ratelimit.allowed", allowed))
+	tracing.End(span, nil)
+	if !allowed {
 		return false, int(ttl.Seconds()), nil
 	}
 	return true, 0, nil
 }
 
-// Ensure UpstashService implements RateLimiterService
-var _ middlewares.RateLimiterService = (*UpstashService)(nil)
+// Ping checks that Redis answers
+func (u *UpstashService) Ping(ctx context.Context) error {
+	return u.Client.Ping(ctx).Err()
+}
+
+// SetOnce stores key with ttl only if it does not exist yet and reports whether it was set
+func (u *UpstashService) SetOnce(key string, ttl time.Duration) (bool, error) {
+	return u.Client.SetNX(context.Background(), key, 1, ttl).Result()
+}
+
+// IncrHashField increments a counter field in the hash stored at key
+func (u *UpstashService) IncrHashField(key, field string) error {
+	return u.Client.HIncrBy(context.Background(), key, field, 1).Err()
+}
+
+// HashFields returns all fields of the hash stored at key
+func (u *UpstashService) HashFields(key string) (map[string]string, error) {
+	return u.Client.HGetAll(context.Background(), key).Result()
+}
+
+// SetValue stores value at key with ttl
+func (u *UpstashService) SetValue(key string, value []byte, ttl time.Duration) error {
+	return u.Client.Set(context.Background(), key, value, ttl).Err()
+}
+
+// GetValue returns the value at key, or nil if it does not exist
+func (u *UpstashService) GetValue(key string) ([]byte, error) {
+	val, err := u.Client.Get(context.Background(), key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	return val, err
+}
+
+// TakeValue atomically returns and deletes the value at key, or nil if it does not exist
+func (u *UpstashService) TakeValue(key string) ([]byte, error) {
+	val, err := u.Client.GetDel(context.Background(), key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	return val, err
+}
+
+// PushList prepends value to the list at key, trimming it to maxLen entries
+func (u *UpstashService) PushList(key string, value string, maxLen int64, ttl time.Duration) error {
+	ctx := context.Background()
+	pipe := u.Client.TxPipeline()
+	pipe.LPush(ctx, key, value)
+	pipe.LTrim(ctx, key, 0, maxLen-1)
+	pipe.Expire(ctx, key, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// ListRange returns up to count entries from the head of the list at key
+func (u *UpstashService) ListRange(key string, count int64) ([]string, error) {
+	return u.Client.LRange(context.Background(), key, 0, count-1).Result()
+}
@@ -0,0 +1,184 @@
+package spam
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// HoneypotSignal scores submissions that filled the hidden honeypot field.
+type HoneypotSignal struct {
+	Score float64
+}
+
+func (s HoneypotSignal) Name() string { return "honeypot" }
+
+func (s HoneypotSignal) Evaluate(sub Submission) []Reason {
+	if strings.TrimSpace(sub.Honeypot) == "" {
+		return nil
+	}
+	return []Reason{{Signal: s.Name(), Score: s.Score, Detail: "hidden field filled"}}
+}
+
+// TimingSignal scores submissions sent too quickly after the form was
+// rendered, or carrying a missing, forged or stale form token.
+type TimingSignal struct {
+	Signer       *FormTokenSigner
+	MinFillTime  time.Duration
+	MaxAge       time.Duration
+	TooFastScore float64
+	InvalidScore float64
+}
+
+func (s TimingSignal) Name() string { return "timing" }
+
+func (s TimingSignal) Evaluate(sub Submission) []Reason {
+	age, err := s.Signer.Age(sub.FormToken)
+	switch {
+	case err != nil:
+		return []Reason{{Signal: s.Name(), Score: s.InvalidScore, Detail: err.Error()}}
+	case age < s.MinFillTime:
+		return []Reason{{Signal: s.Name(), Score: s.TooFastScore, Detail: fmt.Sprintf("submitted %s after render", age.Round(time.Millisecond))}}
+	case s.MaxAge > 0 && age > s.MaxAge:
+		return []Reason{{Signal: s.Name(), Score: s.InvalidScore, Detail: "form token expired"}}
+	}
+	return nil
+}
+
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)\S+|\[url=|<a\s`)
+
+// LinkSignal scores each link beyond MaxLinks in the subject and body.
+type LinkSignal struct {
+	MaxLinks     int
+	ScorePerLink float64
+}
+
+func (s LinkSignal) Name() string { return "links" }
+
+func (s LinkSignal) Evaluate(sub Submission) []Reason {
+	count := len(linkPattern.FindAllString(sub.Subject+"\n"+sub.Body, -1))
+	if count <= s.MaxLinks {
+		return nil
+	}
+	return []Reason{{
+		Signal: s.Name(),
+		Score:  float64(count-s.MaxLinks) * s.ScorePerLink,
+		Detail: fmt.Sprintf("%d links", count),
+	}}
+}
+
+// KeywordSignal scores each blocklisted keyword found in the subject or body.
+// Keywords match whole words, so "seo" does not match "museo"; a keyword of
+// several words matches them in sequence, whatever separates them.
+type KeywordSignal struct {
+	Keywords        []string
+	ScorePerKeyword float64
+}
+
+func (s KeywordSignal) Name() string { return "keywords" }
+
+func (s KeywordSignal) Evaluate(sub Submission) []Reason {
+	text := words(sub.Subject + "\n" + sub.Body)
+	hits := []string{}
+	for _, k := range s.Keywords {
+		if kw := words(k); len(kw) > 0 && containsWords(text, kw) {
+			hits = append(hits, k)
+		}
+	}
+	if len(hits) == 0 {
+		return nil
+	}
+	return []Reason{{
+		Signal: s.Name(),
+		Score:  float64(len(hits)) * s.ScorePerKeyword,
+		Detail: strings.Join(hits, ", "),
+	}}
+}
+
+// words splits lowercased text on anything that is not a letter or digit.
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// containsWords reports whether seq appears as consecutive words of text.
+func containsWords(text, seq []string) bool {
+	for i := 0; i+len(seq) <= len(text); i++ {
+		if slices.Equal(text[i:i+len(seq)], seq) {
+			return true
+		}
+	}
+	return false
+}
+
+// CharsetSignal scores text mostly written outside the allowed scripts and
+// text containing invisible formatting characters used to dodge filters.
+type CharsetSignal struct {
+	AllowedScripts  []*unicode.RangeTable
+	MaxForeignRatio float64
+	ForeignScore    float64
+	InvisibleScore  float64
+}
+
+func (s CharsetSignal) Name() string { return "charset" }
+
+func (s CharsetSignal) Evaluate(sub Submission) []Reason {
+	text := sub.Name + " " + sub.Subject + " " + sub.Body
+	letters, foreign, invisible := 0, 0, 0
+	for _, r := range text {
+		if unicode.Is(unicode.Cf, r) {
+			invisible++
+			continue
+		}
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if !unicode.In(r, s.AllowedScripts...) {
+			foreign++
+		}
+	}
+
+	reasons := []Reason{}
+	if letters > 0 && float64(foreign)/float64(letters) > s.MaxForeignRatio {
+		reasons = append(reasons, Reason{
+			Signal: s.Name(),
+			Score:  s.ForeignScore,
+			Detail: fmt.Sprintf("%d of %d letters outside allowed scripts", foreign, letters),
+		})
+	}
+	if invisible > 0 {
+		reasons = append(reasons, Reason{
+			Signal: s.Name(),
+			Score:  s.InvisibleScore,
+			Detail: fmt.Sprintf("%d invisible characters", invisible),
+		})
+	}
+	return reasons
+}
+
+// Classifier predicts the probability that text is spam; ok is false while untrained.
+type Classifier interface {
+	SpamProbability(text string) (p float64, ok bool)
+}
+
+// ClassifierSignal scores submissions the trained classifier considers spam.
+type ClassifierSignal struct {
+	Classifier Classifier
+	Threshold  float64
+	Score      float64
+}
+
+func (s ClassifierSignal) Name() string { return "classifier" }
+
+func (s ClassifierSignal) Evaluate(sub Submission) []Reason {
+	p, ok := s.Classifier.SpamProbability(sub.Subject + "\n" + sub.Body)
+	if !ok || p < s.Threshold {
+		return nil
+	}
+	return []Reason{{Signal: s.Name(), Score: s.Score, Detail: fmt.Sprintf("p(spam)=%.2f", p)}}
+}
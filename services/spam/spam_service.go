@@ -0,0 +1,92 @@
+package spam
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Decisions returned by the pipeline.
+const (
+	DecisionAccept = "accept"
+	DecisionFlag   = "flag"
+	DecisionReject = "reject"
+)
+
+// Submission is the data spam signals look at.
+type Submission struct {
+	Name      string
+	Subject   string
+	Body      string
+	Honeypot  string
+	FormToken string
+}
+
+// Reason is a single signal's contribution to the spam score.
+type Reason struct {
+	Signal string  `json:"signal"`
+	Score  float64 `json:"score"`
+	Detail string  `json:"detail"`
+}
+
+// Signal inspects a submission and returns zero or more scored reasons.
+type Signal interface {
+	Name() string
+	Evaluate(sub Submission) []Reason
+}
+
+// Verdict is the combined outcome of all signals.
+type Verdict struct {
+	Score    float64  `json:"score"`
+	Decision string   `json:"decision"`
+	Reasons  []Reason `json:"reasons"`
+}
+
+// String formats the verdict for log lines.
+func (v Verdict) String() string {
+	parts := make([]string, 0, len(v.Reasons))
+	for _, r := range v.Reasons {
+		parts = append(parts, fmt.Sprintf("%s(%.1f: %s)", r.Signal, r.Score, r.Detail))
+	}
+	return fmt.Sprintf("score=%.1f decision=%s reasons=[%s]", v.Score, v.Decision, strings.Join(parts, ", "))
+}
+
+// Pipeline sums the scores of its signals and maps the total to a decision.
+type Pipeline struct {
+	Signals         []Signal
+	FlagThreshold   float64
+	RejectThreshold float64
+}
+
+func NewPipeline(flagThreshold, rejectThreshold float64, signals ...Signal) *Pipeline {
+	return &Pipeline{
+		Signals:         signals,
+		FlagThreshold:   flagThreshold,
+		RejectThreshold: rejectThreshold,
+	}
+}
+
+// Use appends signals to the pipeline.
+func (p *Pipeline) Use(signals ...Signal) {
+	p.Signals = append(p.Signals, signals...)
+}
+
+// Evaluate runs every signal and decides accept/flag/reject.
+func (p *Pipeline) Evaluate(sub Submission) Verdict {
+	v := Verdict{Reasons: []Reason{}}
+	for _, s := range p.Signals {
+		for _, r := range s.Evaluate(sub) {
+			v.Score += r.Score
+			v.Reasons = append(v.Reasons, r)
+		}
+	}
+
+	switch {
+	case p.RejectThreshold > 0 && v.Score >= p.RejectThreshold:
+		v.Decision = DecisionReject
+	case p.FlagThreshold > 0 && v.Score >= p.FlagThreshold:
+		v.Decision = DecisionFlag
+	default:
+		v.Decision = DecisionAccept
+	}
+	return v
+}
@@ -0,0 +1,86 @@
+package spam
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrSubmissionNotFound = errors.New("submission not found or expired")
+
+// Store is the key/value persistence for recorded submissions (implemented by the Redis service).
+type Store interface {
+	SetValue(key string, value []byte, ttl time.Duration) error
+	GetValue(key string) ([]byte, error)
+}
+
+// Record is a scored submission kept so an admin can later label it spam or ham.
+type Record struct {
+	ID        string    `json:"id"`
+	Subject   string    `json:"subject"`
+	Body      string    `json:"body"`
+	Verdict   Verdict   `json:"verdict"`
+	Label     string    `json:"label,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Text is the content the classifier is trained on.
+func (r *Record) Text() string {
+	return r.Subject + "\n" + r.Body
+}
+
+// SubmissionLog keeps recent submissions for TTL.
+type SubmissionLog struct {
+	Store Store
+	TTL   time.Duration
+}
+
+func NewSubmissionLog(store Store, ttl time.Duration) *SubmissionLog {
+	return &SubmissionLog{Store: store, TTL: ttl}
+}
+
+// Add records a submission and returns its ID.
+func (l *SubmissionLog) Add(sub Submission, verdict Verdict) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	rec := &Record{
+		ID:        hex.EncodeToString(b),
+		Subject:   sub.Subject,
+		Body:      sub.Body,
+		Verdict:   verdict,
+		CreatedAt: time.Now().UTC(),
+	}
+	return rec.ID, l.Save(rec)
+}
+
+func (l *SubmissionLog) Get(id string) (*Record, error) {
+	data, err := l.Store.GetValue(recordKey(id))
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, ErrSubmissionNotFound
+	}
+	var rec Record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("invalid submission record: %w", err)
+	}
+	return &rec, nil
+}
+
+func (l *SubmissionLog) Save(rec *Record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return l.Store.SetValue(recordKey(rec.ID), data, l.TTL)
+}
+
+func recordKey(id string) string {
+	return "spam:submission:" + id
+}
@@ -0,0 +1,327 @@
+package stepup
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"portfolio-backend/services/captcha"
+	"portfolio-backend/services/email"
+)
+
+// Challenge methods offered to the client.
+const (
+	MethodCaptcha = "captcha"
+	MethodEmail   = "email"
+)
+
+var (
+	ErrNotFound     = errors.New("challenge not found or expired")
+	ErrInvalidToken = errors.New("invalid confirmation token")
+	ErrInProgress   = errors.New("challenge is already being completed")
+	ErrRateLimited  = errors.New("too many confirmation emails for this address")
+	ErrMailFailed   = errors.New("failed to send confirmation email")
+)
+
+// claimTTL bounds how long a completed challenge stays locked while its
+// message is delivered; a crashed delivery becomes retryable afterwards.
+const claimTTL = 2 * time.Minute
+
+// Store is the key/value persistence for pending submissions (implemented by the Redis service).
+type Store interface {
+	SetValue(key string, value []byte, ttl time.Duration) error
+	GetValue(key string) ([]byte, error)
+	TakeValue(key string) ([]byte, error)
+	SetOnce(key string, ttl time.Duration) (bool, error)
+	Allow(ctx context.Context, key string, limit int, ttl time.Duration) (bool, int, error)
+}
+
+// ConfirmationMailer sends the confirmation link for the email challenge.
+type ConfirmationMailer interface {
+	SendConfirmationEmail(ctx context.Context, to, name, confirmURL string) error
+}
+
+// PendingSubmission is a contact submission held until its challenge is completed.
+type PendingSubmission struct {
+	ID               string               `json:"id"`
+	Contact          email.ContactRequest `json:"contact"`
+	ClientIP         string               `json:"client_ip"`
+	Score            float64              `json:"score"`
+	Methods          []string             `json:"methods"`
+	ConfirmTokenHash string               `json:"confirm_token_hash,omitempty"`
+	CreatedAt        time.Time            `json:"created_at"`
+	// SenderValidated records that Contact.From passed validation before the
+	// hold, so delivery does not check the address again
+	SenderValidated bool `json:"sender_validated"`
+}
+
+// Challenge is what the client receives alongside the 428 response.
+type Challenge struct {
+	ID        string   `json:"id"`
+	Methods   []string `json:"methods"`
+	Provider  string   `json:"provider,omitempty"`
+	ExpiresIn int      `json:"expires_in"`
+}
+
+// Service holds borderline submissions and releases them once the visitor
+// passes a second challenge: an interactive captcha (e.g. reCAPTCHA v2
+// checkbox) or an email confirmation link.
+type Service struct {
+	Store      Store
+	TTL        time.Duration
+	MinScore   float64          // lowest score still eligible for step-up
+	Verifier   captcha.Verifier // interactive captcha; nil disables the captcha method
+	Mailer     ConfirmationMailer
+	ConfirmURL string // base URL of the confirmation endpoint; empty disables the email method
+	// ConfirmLimit caps confirmation emails per recipient per hour so the
+	// form cannot be used to mail arbitrary addresses; 0 disables the cap.
+	ConfirmLimit int
+}
+
+func NewService(store Store, ttl time.Duration, minScore float64, verifier captcha.Verifier, mailer ConfirmationMailer, confirmURL string, confirmLimit int) *Service {
+	return &Service{
+		Store:        store,
+		TTL:          ttl,
+		MinScore:     minScore,
+		Verifier:     verifier,
+		Mailer:       mailer,
+		ConfirmURL:   confirmURL,
+		ConfirmLimit: confirmLimit,
+	}
+}
+
+// Eligible reports whether a failed verification should be stepped up
+// rather than rejected: only score failures at or above MinScore qualify.
+// The guarded verifier reports ErrScoreTooLow only after the hostname, age
+// and replay checks passed.
+func (s *Service) Eligible(res *captcha.Result, err error) bool {
+	if res == nil || !errors.Is(err, captcha.ErrScoreTooLow) {
+		return false
+	}
+	return s.MinScore > 0 && res.Score >= s.MinScore && len(s.methods()) > 0
+}
+
+// Hold stores the submission and starts its challenge. The caller must have
+// validated contact.From, since the email method mails that address. When
+// the recipient has had too many confirmation emails, only the captcha
+// method is offered, or ErrRateLimited returned if there is none. If the
+// confirmation email cannot be sent, the submission is dropped and
+// ErrMailFailed returned.
+func (s *Service) Hold(ctx context.Context, contact email.ContactRequest, clientIP string, score float64) (*Challenge, error) {
+	id, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	methods := s.methods()
+	if contains(methods, MethodEmail) && s.ConfirmLimit > 0 {
+		allowed, _, err := s.Store.Allow(ctx, confirmLimitKey(contact.From), s.ConfirmLimit, time.Hour)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			methods = without(methods, MethodEmail)
+			if len(methods) == 0 {
+				return nil, ErrRateLimited
+			}
+		}
+	}
+	pending := PendingSubmission{
+		ID:        id,
+		Contact:   contact,
+		ClientIP:  clientIP,
+		Score:     score,
+		Methods:   methods,
+		CreatedAt: time.Now().UTC(),
+
+		SenderValidated: true,
+	}
+
+	var confirmToken string
+	if contains(pending.Methods, MethodEmail) {
+		confirmToken, err = randomHex(32)
+		if err != nil {
+			return nil, err
+		}
+		pending.ConfirmTokenHash = hashToken(confirmToken)
+	}
+
+	if err := s.save(pending); err != nil {
+		return nil, err
+	}
+
+	if confirmToken != "" {
+		link := s.ConfirmURL + "?" + url.Values{"id": {id}, "token": {confirmToken}}.Encode()
+		if err := s.Mailer.SendConfirmationEmail(ctx, contact.From, contact.Name, link); err != nil {
+			if _, dErr := s.Store.TakeValue(pendingKey(id)); dErr != nil {
+				err = errors.Join(err, dErr)
+			}
+			return nil, fmt.Errorf("%w: %w", ErrMailFailed, err)
+		}
+	}
+
+	challenge := &Challenge{ID: id, Methods: pending.Methods, ExpiresIn: int(s.TTL.Seconds())}
+	if s.Verifier != nil {
+		challenge.Provider = s.Verifier.Provider()
+	}
+	return challenge, nil
+}
+
+// CompleteWithCaptcha claims the submission if the interactive captcha token
+// verifies. The caller delivers it, then calls Finish or Release.
+func (s *Service) CompleteWithCaptcha(ctx context.Context, id, token, remoteIP string) (*PendingSubmission, error) {
+	if s.Verifier == nil {
+		return nil, ErrNotFound
+	}
+	if _, err := s.load(id); err != nil {
+		return nil, err
+	}
+	if _, err := s.Verifier.Verify(ctx, token, remoteIP, captcha.Rule{}); err != nil {
+		return nil, err
+	}
+	return s.claim(id)
+}
+
+// CheckEmailToken reports whether token confirms the submission, without
+// side effects, so the confirmation page can be shown for valid links only.
+func (s *Service) CheckEmailToken(id, token string) error {
+	pending, err := s.load(id)
+	if err != nil {
+		return err
+	}
+	if pending.ConfirmTokenHash == "" ||
+		subtle.ConstantTimeCompare([]byte(pending.ConfirmTokenHash), []byte(hashToken(token))) != 1 {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// CompleteWithEmail claims the submission if the confirmation token matches.
+// The caller delivers it, then calls Finish or Release.
+func (s *Service) CompleteWithEmail(id, token string) (*PendingSubmission, error) {
+	if err := s.CheckEmailToken(id, token); err != nil {
+		return nil, err
+	}
+	return s.claim(id)
+}
+
+// Finish removes a delivered submission.
+func (s *Service) Finish(id string) error {
+	_, err := s.Store.TakeValue(pendingKey(id))
+	s.Store.TakeValue(claimKey(id))
+	return err
+}
+
+// Release unlocks a claimed submission whose delivery failed, so the visitor
+// can complete the challenge again while it has not expired.
+func (s *Service) Release(id string) error {
+	_, err := s.Store.TakeValue(claimKey(id))
+	return err
+}
+
+func (s *Service) methods() []string {
+	methods := []string{}
+	if s.Verifier != nil {
+		methods = append(methods, MethodCaptcha)
+	}
+	if s.Mailer != nil && s.ConfirmURL != "" {
+		methods = append(methods, MethodEmail)
+	}
+	return methods
+}
+
+func (s *Service) save(p PendingSubmission) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return s.Store.SetValue(pendingKey(p.ID), data, s.TTL)
+}
+
+func (s *Service) load(id string) (*PendingSubmission, error) {
+	data, err := s.Store.GetValue(pendingKey(id))
+	if err != nil {
+		return nil, err
+	}
+	return decode(data)
+}
+
+// claim locks the submission for delivery so concurrent completions cannot
+// send it twice. It stays stored until Finish.
+func (s *Service) claim(id string) (*PendingSubmission, error) {
+	fresh, err := s.Store.SetOnce(claimKey(id), claimTTL)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		return nil, ErrInProgress
+	}
+	pending, err := s.load(id)
+	if err != nil {
+		s.Release(id)
+		return nil, err
+	}
+	return pending, nil
+}
+
+func decode(data []byte) (*PendingSubmission, error) {
+	if data == nil {
+		return nil, ErrNotFound
+	}
+	var p PendingSubmission
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid pending submission: %w", err)
+	}
+	return &p, nil
+}
+
+func pendingKey(id string) string {
+	return "stepup:pending:" + id
+}
+
+func claimKey(id string) string {
+	return "stepup:claim:" + id
+}
+
+func confirmLimitKey(recipient string) string {
+	return "stepup:confirm:" + hashToken(strings.ToLower(strings.TrimSpace(recipient)))
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func without(items []string, item string) []string {
+	out := []string{}
+	for _, i := range items {
+		if i != item {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
@@ -1,18 +1,31 @@
 package validation_email
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
+
+	"portfolio-backend/services/tracing"
 )
 
-func CallPrimaryAPI(email string) ([]byte, error) {
-	apiURL := os.Getenv("PRIMARY_EMAIL_VERIFY_URL")
-	apiKey := os.Getenv("PRIMARY_EMAIL_VERIFY_KEY")
+func CallPrimaryAPI(ctx context.Context, email string) (body []byte, err error) {
+	ctx, span := tracing.Start(ctx, "email.CallPrimaryAPI")
+	defer func() { tracing.End(span, err) }()
+
+	apiURL := apis.PrimaryURL
+	apiKey := apis.PrimaryKey
+	if apiURL == "" {
+		return nil, fmt.Errorf("primary API not configured")
+	}
 	url := fmt.Sprintf("%s?api_key=%s&email_address=%s", apiURL, apiKey, email)
 
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil || resp.StatusCode != 200 {
 		if resp != nil {
 			resp.Body.Close()
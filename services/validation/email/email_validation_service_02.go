@@ -1,18 +1,26 @@
 package validation_email
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
+
+	"portfolio-backend/services/tracing"
 )
 
-func CallSecondaryAPI(email string) ([]byte, error) {
-	apiURL := os.Getenv("SECONDARY_EMAIL_VERIFY_URL")
-	apiKey := os.Getenv("SECONDARY_EMAIL_VERIFY_KEY")
+func CallSecondaryAPI(ctx context.Context, email string) (body []byte, err error) {
+	ctx, span := tracing.Start(ctx, "email.CallSecondaryAPI")
+	defer func() { tracing.End(span, err) }()
+
+	apiURL := apis.SecondaryURL
+	apiKey := apis.SecondaryKey
+	if apiURL == "" {
+		return nil, fmt.Errorf("secondary API not configured")
+	}
 	url := fmt.Sprintf("%s?email=%s", apiURL, email)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1,11 +1,23 @@
 package validation_email
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/mail"
+
+	"portfolio-backend/config"
+	"portfolio-backend/services/metrics"
 )
 
+// apis holds the verification endpoints, set at boot by Configure.
+var apis = &config.EmailValidationConfig{}
+
+// Configure sets the primary and secondary verification APIs.
+func Configure(cfg *config.EmailValidationConfig) {
+	apis = cfg
+}
+
 type EmailRequest struct {
 	Email string `json:"email"`
 }
@@ -19,8 +31,9 @@ func (e *ValidationError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Code, e.Message)
 }
 
-func ValidateEmail(email string) (bool, error) {
+func ValidateEmail(ctx context.Context, email string) (bool, error) {
 	if _, err := mail.ParseAddress(email); err != nil {
+		metrics.EmailValidations.WithLabelValues("format", "invalid").Inc()
 		return false, &ValidationError{
 			Code:    "invalid_format",
 			Message: "Invalid email format",
@@ -28,16 +41,34 @@ func ValidateEmail(email string) (bool, error) {
 	}
 
 	// Try primary API first
-	body, err := CallPrimaryAPI(email)
+	body, err := CallPrimaryAPI(ctx, email)
 	if err != nil {
+		metrics.EmailValidations.WithLabelValues("primary", "error").Inc()
 		// Fallback to secondary API
-		body, err = CallSecondaryAPI(email)
+		body, err = CallSecondaryAPI(ctx, email)
 		if err != nil {
+			metrics.EmailValidations.WithLabelValues("secondary", "error").Inc()
 			return false, err
 		}
-		return parseSecondaryAPIResponse(body)
+		valid, err := parseSecondaryAPIResponse(body)
+		recordOutcome("secondary", valid, err)
+		return valid, err
+	}
+	valid, err := parsePrimaryAPIResponse(body)
+	recordOutcome("primary", valid, err)
+	return valid, err
+}
+
+// recordOutcome counts a provider's parsed verdict.
+func recordOutcome(provider string, valid bool, err error) {
+	outcome := "valid"
+	switch {
+	case err != nil:
+		outcome = "error"
+	case !valid:
+		outcome = "invalid"
 	}
-	return parsePrimaryAPIResponse(body)
+	metrics.EmailValidations.WithLabelValues(provider, outcome).Inc()
 }
 
 // Helper for primary API response
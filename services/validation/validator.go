@@ -0,0 +1,166 @@
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Errors maps a request field (its JSON name) to its validation messages.
+type Errors map[string][]string
+
+func (e Errors) add(field, message string) {
+	e[field] = append(e[field], message)
+}
+
+// Message summarises the errors like Laravel: the first message, plus a count of the rest.
+func (e Errors) Message() string {
+	fields := make([]string, 0, len(e))
+	total := 0
+	for field, messages := range e {
+		fields = append(fields, field)
+		total += len(messages)
+	}
+	if total == 0 {
+		return ""
+	}
+	sort.Strings(fields)
+	first := e[fields[0]][0]
+	switch total {
+	case 1:
+		return first
+	case 2:
+		return first + " (and 1 more error)"
+	default:
+		return fmt.Sprintf("%s (and %d more errors)", first, total-1)
+	}
+}
+
+func (e Errors) Error() string {
+	return "validation failed: " + e.Message()
+}
+
+// Struct validates the exported string and integer fields of the struct v
+// points to, using rules from the `validate` tag:
+//
+//	Subject string `json:"subject" validate:"required,max=255"`
+//
+// Rules: required, min=N, max=N (characters for strings, value for
+// integers) and email. Empty optional fields skip the other rules.
+// It returns nil when every field passes.
+func Struct(v interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("validation: %T is not a struct", v)
+	}
+
+	errs := Errors{}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag := sf.Tag.Get("validate")
+		if tag == "" || !sf.IsExported() {
+			continue
+		}
+		field := fieldName(sf)
+		if err := checkField(errs, field, rv.Field(i), strings.Split(tag, ",")); err != nil {
+			return err
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func checkField(errs Errors, field string, value reflect.Value, rules []string) error {
+	label := displayName(field)
+	empty := value.IsZero()
+	if value.Kind() == reflect.String {
+		empty = strings.TrimSpace(value.String()) == ""
+	}
+
+	for _, rule := range rules {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		if name == "required" {
+			if empty {
+				errs.add(field, fmt.Sprintf("The %s field is required.", label))
+				return nil
+			}
+			continue
+		}
+		if empty {
+			continue
+		}
+
+		switch name {
+		case "min", "max":
+			limit, err := strconv.Atoi(arg)
+			if err != nil {
+				return fmt.Errorf("validation: invalid %s rule on %s: %q", name, field, arg)
+			}
+			checkSize(errs, field, label, value, name, limit)
+		case "email":
+			if addr, err := mail.ParseAddress(value.String()); err != nil || addr.Address != value.String() {
+				errs.add(field, fmt.Sprintf("The %s field must be a valid email address.", label))
+			}
+		default:
+			return fmt.Errorf("validation: unknown rule %q on %s", name, field)
+		}
+	}
+	return nil
+}
+
+func checkSize(errs Errors, field, label string, value reflect.Value, rule string, limit int) {
+	var size int
+	var unit string
+	switch value.Kind() {
+	case reflect.String:
+		size, unit = utf8.RuneCountInString(value.String()), " characters"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size = int(value.Int())
+	default:
+		return
+	}
+
+	switch {
+	case rule == "min" && size < limit:
+		errs.add(field, fmt.Sprintf("The %s field must be at least %d%s.", label, limit, unit))
+	case rule == "max" && size > limit:
+		errs.add(field, fmt.Sprintf("The %s field must not be greater than %d%s.", label, limit, unit))
+	}
+}
+
+// fieldName returns the JSON name of a struct field.
+func fieldName(sf reflect.StructField) string {
+	if name, _, _ := strings.Cut(sf.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+	return sf.Name
+}
+
+// displayName turns a field name into words: "recaptchaToken" and
+// "delivery_id" become "recaptcha token" and "delivery id".
+func displayName(field string) string {
+	var b strings.Builder
+	for i, r := range field {
+		switch {
+		case r == '_' || r == '-':
+			b.WriteRune(' ')
+		case unicode.IsUpper(r):
+			if i > 0 {
+				b.WriteRune(' ')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,88 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+)
+
+type contactForm struct {
+	Name           string `json:"name" validate:"required,max=5"`
+	Email          string `json:"email" validate:"required,email"`
+	RecaptchaToken string `json:"recaptchaToken" validate:"min=3"`
+	Count          int    `json:"count" validate:"max=2"`
+	internal       string `validate:"required"`
+}
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name string
+		form contactForm
+		want Errors
+	}{
+		{
+			name: "valid",
+			form: contactForm{Name: "Ada", Email: "ada@example.com"},
+		},
+		{
+			name: "required fields",
+			form: contactForm{Name: "  "},
+			want: Errors{
+				"name":  {"The name field is required."},
+				"email": {"The email field is required."},
+			},
+		},
+		{
+			name: "sizes and email",
+			form: contactForm{Name: "Ada Lovelace", Email: "Ada <ada@example.com>", RecaptchaToken: "ab", Count: 3},
+			want: Errors{
+				"name":           {"The name field must not be greater than 5 characters."},
+				"email":          {"The email field must be a valid email address."},
+				"recaptchaToken": {"The recaptcha token field must be at least 3 characters."},
+				"count":          {"The count field must not be greater than 2."},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Struct(&tt.form)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Struct error = %v", err)
+				}
+				return
+			}
+			errs, ok := err.(Errors)
+			if !ok || !reflect.DeepEqual(errs, tt.want) {
+				t.Errorf("Struct error = %#v, want %#v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructInvalidRule(t *testing.T) {
+	var form struct {
+		Name string `json:"name" validate:"max=five"`
+	}
+	form.Name = "Ada"
+	err := Struct(&form)
+	if _, ok := err.(Errors); ok || err == nil {
+		t.Errorf("Struct error = %#v, want a non-validation error", err)
+	}
+}
+
+func TestErrorsMessage(t *testing.T) {
+	tests := []struct {
+		errs Errors
+		want string
+	}{
+		{Errors{}, ""},
+		{Errors{"name": {"The name field is required."}}, "The name field is required."},
+		{Errors{"name": {"A."}, "email": {"B."}}, "B. (and 1 more error)"},
+		{Errors{"name": {"A.", "C."}, "email": {"B."}}, "B. (and 2 more errors)"},
+	}
+	for _, tt := range tests {
+		if got := tt.errs.Message(); got != tt.want {
+			t.Errorf("Message() = %q, want %q", got, tt.want)
+		}
+	}
+}
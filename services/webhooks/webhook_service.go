@@ -0,0 +1,349 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"portfolio-backend/services/notification"
+)
+
+// Delivery statuses.
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+const maxAttemptHistory = 10
+
+var ErrDeliveryNotFound = errors.New("delivery not found or expired")
+
+// Store is the persistence for deliveries (implemented by the Redis service).
+type Store interface {
+	SetValue(key string, value []byte, ttl time.Duration) error
+	GetValue(key string) ([]byte, error)
+	PushList(key string, value string, maxLen int64, ttl time.Duration) error
+	ListRange(key string, count int64) ([]string, error)
+}
+
+// Subscription is an endpoint receiving signed contact events.
+type Subscription struct {
+	ID     string   `json:"id"`
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"` // empty subscribes to every event
+}
+
+// Wants reports whether the subscription receives eventType.
+func (s Subscription) Wants(eventType string) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == eventType || e == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Envelope is the JSON body posted to subscribers.
+type Envelope struct {
+	ID         string             `json:"id"`
+	Type       string             `json:"type"`
+	OccurredAt time.Time          `json:"occurred_at"`
+	Data       notification.Event `json:"data"`
+}
+
+// Attempt is one HTTP try of a delivery.
+type Attempt struct {
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// Delivery is the log entry of an event sent to one subscription.
+type Delivery struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscription_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       []Attempt       `json:"attempts"`      // most recent maxAttemptHistory, manual ones included
+	AttemptCount   int             `json:"attempt_count"` // automatic attempts so far, never trimmed
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// Service signs and delivers events to subscriptions, retrying failures with
+// exponential backoff and keeping a delivery log for inspection and redelivery.
+// Scheduled retries live in memory and are lost on restart; the log is not.
+type Service struct {
+	Subscriptions []Subscription
+	Store         Store
+	HTTP          *http.Client
+	MaxAttempts   int
+	BaseBackoff   time.Duration
+	LogTTL        time.Duration
+	LogSize       int64
+
+	mu sync.Mutex // serializes read-modify-write of a delivery record
+}
+
+func NewService(subs []Subscription, store Store, maxAttempts int, baseBackoff, logTTL time.Duration) *Service {
+	return &Service{
+		Subscriptions: subs,
+		Store:         store,
+		HTTP:          &http.Client{Timeout: 10 * time.Second, Transport: otelhttp.NewTransport(http.DefaultTransport)}, // sends traceparent so subscribers can join the trace
+		MaxAttempts:   maxAttempts,
+		BaseBackoff:   baseBackoff,
+		LogTTL:        logTTL,
+		LogSize:       200,
+	}
+}
+
+// Name lets the service act as a notification channel.
+func (s *Service) Name() string { return "subscriptions" }
+
+// Notify queues the event for every subscription that wants it. Delivery
+// happens in the background, so the error only reports logging failures.
+func (s *Service) Notify(ctx context.Context, e notification.Event) error {
+	// Deliveries outlive the request but keep its values for logging
+	ctx = context.WithoutCancel(ctx)
+
+	var errs []error
+	for _, sub := range s.Subscriptions {
+		if !sub.Wants(e.Type) {
+			continue
+		}
+		d, err := s.create(sub, e)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("subscription %s: %w", sub.ID, err))
+			continue
+		}
+		go s.attempt(ctx, sub, d.ID)
+	}
+	return errors.Join(errs...)
+}
+
+// Redeliver sends a logged delivery again right away and returns the updated entry.
+func (s *Service) Redeliver(ctx context.Context, id string) (*Delivery, error) {
+	d, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	sub, ok := s.subscription(d.SubscriptionID)
+	if !ok {
+		return nil, fmt.Errorf("subscription %s no longer configured", d.SubscriptionID)
+	}
+	return s.send(ctx, sub, id, true)
+}
+
+// Get returns a logged delivery.
+func (s *Service) Get(id string) (*Delivery, error) {
+	data, err := s.Store.GetValue(deliveryKey(id))
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, ErrDeliveryNotFound
+	}
+	var d Delivery
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("invalid delivery record: %w", err)
+	}
+	return &d, nil
+}
+
+// Recent returns up to count most recent deliveries, optionally for one subscription.
+func (s *Service) Recent(subscriptionID string, count int64) ([]*Delivery, error) {
+	ids, err := s.Store.ListRange(indexKey(subscriptionID), count)
+	if err != nil {
+		return nil, err
+	}
+	deliveries := make([]*Delivery, 0, len(ids))
+	for _, id := range ids {
+		d, err := s.Get(id)
+		if errors.Is(err, ErrDeliveryNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, nil
+}
+
+func (s *Service) create(sub Subscription, e notification.Event) (*Delivery, error) {
+	id, err := randomID()
+	if err != nil {
+		return nil, err
+	}
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now().UTC()
+	}
+	payload, err := json.Marshal(Envelope{ID: id, Type: e.Type, OccurredAt: e.OccurredAt, Data: e})
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Delivery{
+		ID:             id,
+		SubscriptionID: sub.ID,
+		EventType:      e.Type,
+		Payload:        payload,
+		Status:         StatusPending,
+		Attempts:       []Attempt{},
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := s.save(d); err != nil {
+		return nil, err
+	}
+	for _, key := range []string{indexKey(""), indexKey(sub.ID)} {
+		if err := s.Store.PushList(key, id, s.LogSize, s.LogTTL); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// attempt performs a scheduled try and schedules the next one on failure.
+func (s *Service) attempt(ctx context.Context, sub Subscription, id string) {
+	d, err := s.send(ctx, sub, id, false)
+	if err != nil {
+		slog.ErrorContext(ctx, "webhook delivery error", "delivery", id, "subscription", sub.ID, "error", err)
+		return
+	}
+	if d.Status == StatusPending && d.NextAttemptAt != nil {
+		time.AfterFunc(time.Until(*d.NextAttemptAt), func() { s.attempt(ctx, sub, id) })
+	}
+}
+
+// send posts the delivery once and records the outcome. Manual redeliveries
+// don't schedule further retries.
+func (s *Service) send(ctx context.Context, sub Subscription, id string, manual bool) (*Delivery, error) {
+	s.mu.Lock()
+	d, err := s.Get(id)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	attempt := s.post(ctx, sub, d)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if latest, err := s.Get(id); err == nil {
+		d = latest
+	}
+	d.Attempts = append(d.Attempts, attempt)
+	if len(d.Attempts) > maxAttemptHistory {
+		d.Attempts = d.Attempts[len(d.Attempts)-maxAttemptHistory:]
+	}
+	if !manual {
+		d.AttemptCount++
+	}
+	d.NextAttemptAt = nil
+
+	switch {
+	case attempt.Error == "":
+		d.Status = StatusSucceeded
+		slog.InfoContext(ctx, "webhook delivered", "delivery", d.ID, "subscription", sub.ID, "status", attempt.StatusCode)
+	case manual || d.AttemptCount >= s.MaxAttempts:
+		d.Status = StatusFailed
+		slog.WarnContext(ctx, "webhook delivery failed", "delivery", d.ID, "subscription", sub.ID, "attempts", d.AttemptCount, "error", attempt.Error)
+	default:
+		d.Status = StatusPending
+		next := time.Now().Add(s.BaseBackoff << (d.AttemptCount - 1)).UTC()
+		d.NextAttemptAt = &next
+		slog.InfoContext(ctx, "webhook delivery retry scheduled", "delivery", d.ID, "subscription", sub.ID, "next", next.Format(time.RFC3339), "error", attempt.Error)
+	}
+	return d, s.save(d)
+}
+
+func (s *Service) post(ctx context.Context, sub Subscription, d *Delivery) Attempt {
+	start := time.Now()
+	attempt := Attempt{At: start.UTC()}
+
+	ts := strconv.FormatInt(start.Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Id", d.ID)
+	req.Header.Set("X-Event-Type", d.EventType)
+	req.Header.Set("X-Event-Timestamp", ts)
+	req.Header.Set("X-Event-Signature", "sha256="+notification.Sign(sub.Secret, ts, d.Payload))
+
+	resp, err := s.HTTP.Do(req)
+	if err != nil {
+		attempt.Error = err.Error()
+		attempt.DurationMs = time.Since(start).Milliseconds()
+		return attempt
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode >= 300 {
+		attempt.Error = fmt.Sprintf("unexpected status %d", resp.StatusCode)
+	}
+	attempt.DurationMs = time.Since(start).Milliseconds()
+	return attempt
+}
+
+func (s *Service) save(d *Delivery) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return s.Store.SetValue(deliveryKey(d.ID), data, s.LogTTL)
+}
+
+func (s *Service) subscription(id string) (Subscription, bool) {
+	for _, sub := range s.Subscriptions {
+		if sub.ID == id {
+			return sub, true
+		}
+	}
+	return Subscription{}, false
+}
+
+func deliveryKey(id string) string {
+	return "webhooks:delivery:" + id
+}
+
+func indexKey(subscriptionID string) string {
+	if subscriptionID == "" {
+		return "webhooks:deliveries"
+	}
+	return "webhooks:deliveries:" + subscriptionID
+}
+
+func randomID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Ensure Service implements notification.Notifier
+var _ notification.Notifier = (*Service)(nil)
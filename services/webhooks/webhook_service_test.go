@@ -0,0 +1,130 @@
+package webhooks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"portfolio-backend/services/notification"
+)
+
+// memoryStore is an in-memory Store.
+type memoryStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	lists  map[string][]string
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{values: map[string][]byte{}, lists: map[string][]string{}}
+}
+
+func (m *memoryStore) SetValue(key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = value
+	return nil
+}
+
+func (m *memoryStore) GetValue(key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.values[key], nil
+}
+
+func (m *memoryStore) PushList(key string, value string, maxLen int64, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lists[key] = append([]string{value}, m.lists[key]...)
+	return nil
+}
+
+func (m *memoryStore) ListRange(key string, count int64) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lists[key], nil
+}
+
+func TestSendStopsAfterMaxAttempts(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts int
+		manual      int // redeliveries made before the automatic attempts
+	}{
+		{"below history size", 3, 0},
+		{"above history size", 15, 0},
+		{"manual redeliveries don't count", 4, 3},
+		{"manual redeliveries beyond history", 12, 11},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			}))
+			defer srv.Close()
+
+			sub := Subscription{ID: "test", URL: srv.URL, Secret: "secret"}
+			s := NewService([]Subscription{sub}, newMemoryStore(), tt.maxAttempts, time.Millisecond, time.Hour)
+			d, err := s.create(sub, notification.Event{Type: "contact.received"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+
+			for i := 0; i < tt.manual; i++ {
+				if _, err := s.send(ctx, sub, d.ID, true); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for i := 1; i <= tt.maxAttempts; i++ {
+				d, err = s.send(ctx, sub, d.ID, false)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if d.AttemptCount != i {
+					t.Fatalf("AttemptCount = %d after %d automatic attempts", d.AttemptCount, i)
+				}
+				wantStatus := StatusPending
+				if i == tt.maxAttempts {
+					wantStatus = StatusFailed
+				}
+				if d.Status != wantStatus {
+					t.Fatalf("attempt %d: Status = %s, want %s", i, d.Status, wantStatus)
+				}
+				if (d.NextAttemptAt != nil) != (wantStatus == StatusPending) {
+					t.Fatalf("attempt %d: NextAttemptAt = %v with status %s", i, d.NextAttemptAt, d.Status)
+				}
+			}
+
+			if want := min(tt.maxAttempts+tt.manual, maxAttemptHistory); len(d.Attempts) != want {
+				t.Errorf("len(Attempts) = %d, want %d", len(d.Attempts), want)
+			}
+		})
+	}
+}
+
+func TestSendSucceeds(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Event-Signature") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	sub := Subscription{ID: "test", URL: srv.URL, Secret: "secret"}
+	s := NewService([]Subscription{sub}, newMemoryStore(), 3, time.Millisecond, time.Hour)
+	d, err := s.create(sub, notification.Event{Type: "contact.received"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err = s.send(context.Background(), sub, d.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Status != StatusSucceeded || d.AttemptCount != 1 || d.Attempts[0].StatusCode != http.StatusOK {
+		t.Errorf("delivery = status %s, count %d, attempts %+v", d.Status, d.AttemptCount, d.Attempts)
+	}
+}
@@ -1,6 +1,12 @@
 package utils
 
-import "os"
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 func GetEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -8,3 +14,39 @@ func GetEnvOrDefault(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// GetEnvList splits a comma-separated env value into trimmed, non-empty items.
+func GetEnvList(key, defaultValue string) []string {
+	items := []string{}
+	for _, item := range strings.Split(GetEnvOrDefault(key, defaultValue), ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// GetEnvDurationOrDefault parses a duration as ParseDuration does.
+func GetEnvDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	if d, err := ParseDuration(value); err == nil {
+		return d
+	}
+	return defaultValue
+}
+
+// ParseDuration parses a Go duration ("90s", "10m") or a plain number of seconds.
+func ParseDuration(value string) (time.Duration, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return d, nil
+	}
+	secs, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return time.Duration(secs) * time.Second, nil
+}